@@ -32,10 +32,6 @@ func (o *Order) Rules() []*v.FieldRules {
 	}
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 func main() {
 	doc := openapi.DocBase("Example API (gorilla)", "Demonstrates apivalidation with gorilla/mux", "0.1.0")
 
@@ -51,10 +47,8 @@ func main() {
 
 	r.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
 		var order Order
-		if err := v.DecodeAndValidate(r.Body, &order); err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		if err := v.DecodeAndValidateContext(r.Context(), r.Body, &order); err != nil {
+			v.WriteProblem(w, r, err)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
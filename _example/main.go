@@ -9,7 +9,7 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -33,48 +33,22 @@ func (o *Order) Rules() []*v.FieldRules {
 	}
 }
 
-// ErrorResponse is a standard error envelope.
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 func main() {
 	// Build the OpenAPI spec.
 	doc := openapi.DocBase("Example API", "Demonstrates apivalidation", "0.1.0")
 
-	openapi.Post(doc, "/orders", "createOrder", openapi.Endpoint{
-		Summary:  "Create an order",
-		Request:  Order{},
-		Response: Order{},
-		Responses: map[string]openapi.Response{
-			"200": {Desc: "Created order", Bodies: []any{Order{}}},
-			"400": {Desc: "Validation error", Bodies: []any{ErrorResponse{}}},
-		},
-	})
-
-	// Swagger UI
-	http.Handle("/swagger/", openapi.SwaggerHandlerMust("/swagger/", doc))
-
-	// API endpoint
-	http.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+	mux := http.NewServeMux()
 
-		var order Order
-		if err := v.DecodeAndValidate(r.Body, &order); err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
-			return
-		}
+	// Register the endpoint and its documentation together.
+	openapi.Handle(mux, doc, "POST /orders", "createOrder",
+		func(_ context.Context, order *Order) (*Order, error) {
+			return order, nil
+		})
 
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(order)
-	})
+	// Swagger UI
+	mux.Handle("/swagger/", openapi.SwaggerHandlerMust("/swagger/", doc))
 
 	fmt.Println("Listening on http://localhost:8080")
 	fmt.Println("Swagger UI: http://localhost:8080/swagger/")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	log.Fatal(http.ListenAndServe(":8080", mux))
 }
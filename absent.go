@@ -27,9 +27,9 @@ func (r absentRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaR
 		ref.Value.Description += " "
 	}
 	if r.skipNil {
-		ref.Value.Description += "empty"
+		ref.Value.Description += Messages.Describe("describe_empty", "empty", nil)
 	} else {
-		ref.Value.Description += "null"
+		ref.Value.Description += Messages.Describe("describe_null", "null", nil)
 	}
 	return nil
 }
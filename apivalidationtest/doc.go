@@ -0,0 +1,4 @@
+// Package apivalidationtest provides testing utilities for types validated
+// with [apivalidation]. [CheckParity] checks that the generated schema of a
+// type documents exactly what [apivalidation.Validate] enforces.
+package apivalidationtest
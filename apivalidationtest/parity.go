@@ -0,0 +1,337 @@
+package apivalidationtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	av "github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxGeneratedLength caps the length of generated strings and arrays.
+// Longer maxLength and maxItems bounds are not checked.
+const maxGeneratedLength = 1 << 16
+
+// Mismatch is a boundary value that the schema and [apivalidation.Validate]
+// disagree on.
+type Mismatch struct {
+	// Pointer is the JSON Pointer of the changed value.
+	Pointer string
+	// Keyword is the schema keyword the value is a boundary of.
+	Keyword string
+	// Value is the JSON value substituted at Pointer. It is nil if the
+	// property was omitted.
+	Value any
+	// SchemaRejects reports whether the schema rejects the document.
+	SchemaRejects bool
+	// ValidateRejects reports whether Validate rejects the document.
+	ValidateRejects bool
+}
+
+func (m Mismatch) String() string {
+	value := "omitted"
+	if m.Value != nil {
+		b, _ := json.Marshal(m.Value)
+		value = "= " + string(b)
+	}
+	return fmt.Sprintf("%s %s (%s): schema %s, Validate %s", m.Pointer, value, m.Keyword, verdict(m.SchemaRejects), verdict(m.ValidateRejects))
+}
+
+func verdict(rejects bool) string {
+	if rejects {
+		return "rejects"
+	}
+	return "accepts"
+}
+
+// CheckParity reports an error on t for each [Mismatch] found by
+// [Mismatches]. It fails t immediately if value is not valid.
+//
+//	func TestOrderParity(t *testing.T) {
+//	    apivalidationtest.CheckParity(t, Order{Status: "open", Qty: 1})
+//	}
+func CheckParity(t testing.TB, value any) {
+	t.Helper()
+	mismatches, err := Mismatches(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mismatches {
+		t.Errorf("%T: %s", value, m)
+	}
+}
+
+// Mismatches changes one value of value's JSON encoding at a time to a
+// boundary of its schema, and returns the changes that the schema and
+// [apivalidation.Validate] disagree on: one rejects the document at the
+// changed value and the other does not. value must be valid, and should set
+// the optional nested structs, slices and maps to check.
+//
+// Boundaries are generated for these keywords:
+//   - minimum and maximum: the bound and the first integer past it
+//   - minLength and maxLength: strings of the bound's length and one past it
+//   - minItems and maxItems: arrays of the bound's length and one past it
+//   - enum: a value that is not a member
+//   - required: the property omitted
+//
+// The document is validated against the schema with
+// [apivalidation.ValidateJSON]. It is decoded into a new value of value's
+// type for Validate; a decoding error counts as a rejection of every value.
+func Mismatches(value any) ([]Mismatch, error) {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		return nil, fmt.Errorf("apivalidationtest: nil value")
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema, err := av.NewSchemaRefForValue(value)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := av.Validate(value); err != nil {
+		return nil, fmt.Errorf("apivalidationtest: %T is not valid: %w", value, err)
+	}
+	if err := av.ValidateJSON(schema, b); err != nil {
+		return nil, fmt.Errorf("apivalidationtest: %T does not match its schema: %w", value, err)
+	}
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	var cases []parityCase
+	collectCases(schema.Value, nil, doc, &cases)
+	var out []Mismatch
+	for _, c := range cases {
+		raw, err := json.Marshal(c.apply(doc))
+		if err != nil {
+			return nil, err
+		}
+		pointer := jsonPointer(c.path)
+		schemaRejects := rejectsAt(av.ValidateJSON(schema, raw), pointer)
+		validateRejects := true
+		dst := reflect.New(t).Interface()
+		if json.Unmarshal(raw, dst) == nil {
+			validateRejects = rejectsAt(av.Validate(dst), pointer)
+		}
+		if schemaRejects != validateRejects {
+			out = append(out, Mismatch{
+				Pointer:         pointer,
+				Keyword:         c.keyword,
+				Value:           c.value,
+				SchemaRejects:   schemaRejects,
+				ValidateRejects: validateRejects,
+			})
+		}
+	}
+	return out, nil
+}
+
+// parityCase replaces the value at path with value, or omits it.
+type parityCase struct {
+	path    []string
+	keyword string
+	value   any
+	omit    bool
+}
+
+// collectCases adds the cases for the schema s of the value v at path, and
+// for the values nested in v. v is nil if the value is absent.
+func collectCases(s *openapi3.Schema, path []string, v any, cases *[]parityCase) {
+	add := func(keyword string, value any) {
+		*cases = append(*cases, parityCase{path: path, keyword: keyword, value: value})
+	}
+	switch {
+	case s.Type.Is(openapi3.TypeInteger), s.Type.Is(openapi3.TypeNumber):
+		if s.Min != nil {
+			if s.ExclusiveMin {
+				add("exclusiveMinimum", *s.Min)
+				add("exclusiveMinimum", math.Floor(*s.Min)+1)
+			} else {
+				add("minimum", *s.Min)
+				add("minimum", math.Ceil(*s.Min)-1)
+			}
+		}
+		if s.Max != nil {
+			if s.ExclusiveMax {
+				add("exclusiveMaximum", *s.Max)
+				add("exclusiveMaximum", math.Ceil(*s.Max)-1)
+			} else {
+				add("maximum", *s.Max)
+				add("maximum", math.Floor(*s.Max)+1)
+			}
+		}
+	case s.Type.Is(openapi3.TypeString):
+		if s.MinLength > 0 && s.MinLength <= maxGeneratedLength {
+			add("minLength", strings.Repeat("a", int(s.MinLength)))
+			add("minLength", strings.Repeat("a", int(s.MinLength)-1))
+		}
+		if s.MaxLength != nil && *s.MaxLength < maxGeneratedLength {
+			add("maxLength", strings.Repeat("a", int(*s.MaxLength)))
+			add("maxLength", strings.Repeat("a", int(*s.MaxLength)+1))
+		}
+	case s.Type.Is(openapi3.TypeArray):
+		items, _ := v.([]any)
+		if len(items) > 0 {
+			if s.MinItems > 0 && s.MinItems <= uint64(len(items)) {
+				add("minItems", slices.Clone(items[:s.MinItems]))
+				add("minItems", slices.Clone(items[:s.MinItems-1]))
+			}
+			if s.MaxItems != nil && *s.MaxItems < maxGeneratedLength {
+				add("maxItems", repeatItems(items, int(*s.MaxItems)))
+				add("maxItems", repeatItems(items, int(*s.MaxItems)+1))
+			}
+			if s.Items != nil && s.Items.Value != nil {
+				collectCases(s.Items.Value, appendPath(path, "0"), items[0], cases)
+			}
+		}
+	case s.Type.Is(openapi3.TypeObject):
+		obj, _ := v.(map[string]any)
+		if obj == nil {
+			break
+		}
+		for _, name := range sortedKeys(s.Properties) {
+			prop := s.Properties[name]
+			if prop.Value == nil {
+				continue
+			}
+			child, present := obj[name]
+			if present && slices.Contains(s.Required, name) {
+				*cases = append(*cases, parityCase{path: appendPath(path, name), keyword: "required", omit: true})
+			}
+			if !present && prop.Value.Type.Is(openapi3.TypeObject) {
+				continue // no sample to change
+			}
+			collectCases(prop.Value, appendPath(path, name), child, cases)
+		}
+		if ap := s.AdditionalProperties.Schema; ap != nil && ap.Value != nil {
+			for _, key := range sortedKeys(obj) {
+				if _, ok := s.Properties[key]; !ok {
+					collectCases(ap.Value, appendPath(path, key), obj[key], cases)
+					break
+				}
+			}
+		}
+	}
+	if len(s.Enum) > 0 {
+		if nonMember, ok := enumNonMember(s); ok {
+			add("enum", nonMember)
+		}
+	}
+}
+
+// enumNonMember returns a value of the schema's type not in its enum.
+func enumNonMember(s *openapi3.Schema) (any, bool) {
+	switch {
+	case s.Type.Is(openapi3.TypeString):
+		candidate := "not-a-member"
+		for slices.Contains(s.Enum, any(candidate)) {
+			candidate += "!"
+		}
+		return candidate, true
+	case s.Type.Is(openapi3.TypeInteger), s.Type.Is(openapi3.TypeNumber):
+		candidate := 0.0
+		for _, e := range s.Enum {
+			if f, ok := toFloat(e); ok && f >= candidate {
+				candidate = math.Floor(f) + 1
+			}
+		}
+		return candidate, true
+	}
+	return nil, false
+}
+
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// apply returns a copy of doc with the case's change.
+func (c parityCase) apply(doc any) any {
+	return setPath(doc, c.path, c.value, c.omit)
+}
+
+func setPath(v any, path []string, value any, omit bool) any {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = child
+		}
+		if len(path) == 1 && omit {
+			delete(out, path[0])
+			return out
+		}
+		out[path[0]] = setPath(v[path[0]], path[1:], value, omit)
+		return out
+	case []any:
+		out := slices.Clone(v)
+		i, _ := strconv.Atoi(path[0])
+		out[i] = setPath(v[i], path[1:], value, omit)
+		return out
+	}
+	return v
+}
+
+// rejectsAt reports whether err has a failure at pointer or below it.
+func rejectsAt(err error, pointer string) bool {
+	for _, fe := range av.FieldErrors(err) {
+		if fe.Pointer == pointer || strings.HasPrefix(fe.Pointer, pointer+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func repeatItems(items []any, n int) []any {
+	out := make([]any, n)
+	for i := range out {
+		out[i] = items[i%len(items)]
+	}
+	return out
+}
+
+func appendPath(path []string, key string) []string {
+	return append(slices.Clip(path), key)
+}
+
+func jsonPointer(path []string) string {
+	var b strings.Builder
+	for _, key := range path {
+		key = strings.ReplaceAll(key, "~", "~0")
+		key = strings.ReplaceAll(key, "/", "~1")
+		b.WriteString("/" + key)
+	}
+	return b.String()
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package apivalidationtest_test
+
+import (
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/Gobd/apivalidation/apivalidationtest"
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type parityLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+func (l *parityLine) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&l.SKU, v.Required),
+		v.Field(&l.Qty, v.Min(5), v.Max(10)),
+	}
+}
+
+type parityOrder struct {
+	Status string       `json:"status"`
+	Lines  []parityLine `json:"lines"`
+}
+
+func (o *parityOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&o.Status, v.Required, v.In("open", "closed")),
+		v.Field(&o.Lines, v.MinItems(1), v.MaxItems(3)),
+	}
+}
+
+// shortRule documents a shorter maximum length than it enforces.
+type shortRule struct{}
+
+func (shortRule) Validate(value any) error {
+	return validation.Length(0, 10).Validate(value)
+}
+
+func (shortRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	ref.Value.WithMaxLength(5)
+	return nil
+}
+
+type parityDrift struct {
+	Code  string `json:"code"`
+	Count int    `json:"count"`
+}
+
+func (d *parityDrift) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&d.Code, shortRule{}),
+		v.Field(&d.Count, v.Min(1)),
+	}
+}
+
+func TestCheckParity(t *testing.T) {
+	apivalidationtest.CheckParity(t, parityOrder{Status: "open", Lines: []parityLine{{SKU: "a", Qty: 5}}})
+}
+
+func TestMismatches(t *testing.T) {
+	mismatches, err := apivalidationtest.Mismatches(&parityDrift{Code: "abc", Count: 2})
+	require.NoError(t, err)
+	require.Len(t, mismatches, 2)
+
+	assert.Equal(t, "/code", mismatches[0].Pointer)
+	assert.Equal(t, "maxLength", mismatches[0].Keyword)
+	assert.Equal(t, "aaaaaa", mismatches[0].Value)
+	assert.True(t, mismatches[0].SchemaRejects)
+	assert.False(t, mismatches[0].ValidateRejects)
+
+	// Min skips zero values, which the schema's minimum does not.
+	assert.Equal(t, `/count = 0 (minimum): schema rejects, Validate accepts`, mismatches[1].String())
+}
+
+func TestMismatches_InvalidValue(t *testing.T) {
+	_, err := apivalidationtest.Mismatches(parityOrder{Status: "lost"})
+	assert.ErrorContains(t, err, "is not valid")
+}
@@ -0,0 +1,237 @@
+package apivalidation
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ErrParamInvalid is reported by [Bind] when a path, query, header or cookie
+// value cannot be converted to the field type.
+var ErrParamInvalid = validation.NewError("bind_invalid", "must be a valid {{.type}}")
+
+// paramLocations are the struct tags read by [Bind], in OpenAPI "in" order.
+var paramLocations = [...]string{"path", "query", "header", "cookie"}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// paramField is a struct field bound to a request parameter.
+type paramField struct {
+	index  []int
+	name   string // parameter name from the tag
+	in     string // path, query, header or cookie
+	errKey string // key used by Validate for this field
+	typ    reflect.Type
+}
+
+// paramFields returns the fields of t tagged with a parameter location,
+// recursing into embedded (non-pointer) structs.
+func paramFields(t reflect.Type) []paramField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var out []paramField
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			for _, pf := range paramFields(sf.Type) {
+				pf.index = append([]int{i}, pf.index...)
+				out = append(out, pf)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		for _, in := range paramLocations {
+			name := strings.Split(sf.Tag.Get(in), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			errKey := strings.Split(sf.Tag.Get("json"), ",")[0]
+			if errKey == "" || errKey == "-" {
+				errKey = sf.Name
+			}
+			out = append(out, paramField{index: []int{i}, name: name, in: in, errKey: errKey, typ: sf.Type})
+			break
+		}
+	}
+	return out
+}
+
+// Bind fills dst, a pointer to a struct, from the path, query, header and
+// cookie values of r. Fields opt in with a tag naming the parameter:
+//
+//	type ListOrders struct {
+//	    ID     string `path:"id"`
+//	    Limit  int    `query:"limit"`
+//	    Tenant string `header:"X-Tenant"`
+//	}
+//
+// Values are converted to the field type: strings, booleans, integers,
+// floats, pointers to these, [encoding.TextUnmarshaler] implementations and
+// slices of any of them. Query slices take repeated parameters; path, header
+// and cookie slices are comma separated. Absent parameters leave the field
+// untouched. Conversion failures are returned as [ValidationErrors] keyed by
+// parameter name.
+//
+// Path values come from [http.Request.PathValue], so routes must be
+// registered on an [http.ServeMux] with Go 1.22 patterns.
+func Bind(r *http.Request, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a non-nil pointer to a struct, got %T", dst)
+	}
+	sv := rv.Elem()
+
+	var query url.Values
+	errs := validation.Errors{}
+	for _, pf := range paramFields(sv.Type()) {
+		var values []string
+		switch pf.in {
+		case "path":
+			if v := r.PathValue(pf.name); v != "" {
+				values = []string{v}
+			}
+		case "query":
+			if query == nil {
+				query = r.URL.Query()
+			}
+			values = query[pf.name]
+		case "header":
+			values = r.Header.Values(pf.name)
+		case "cookie":
+			if c, err := r.Cookie(pf.name); err == nil {
+				values = []string{c.Value}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		field := sv.FieldByIndex(pf.index)
+		if pf.in != "query" && listParam(field) {
+			values = strings.Split(values[0], ",")
+		}
+		if err := setParam(field, values); err != nil {
+			errs[pf.name] = ErrParamInvalid.SetParams(map[string]any{"type": paramTypeName(pf.typ)})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// BindAndValidate is like [Bind], then normalizes and validates dst with
+// r's context. Validation errors for parameter fields are keyed by
+// parameter name, matching the errors returned by Bind.
+func BindAndValidate(r *http.Request, dst any) error {
+	if err := Bind(r, dst); err != nil {
+		return err
+	}
+	ctx := r.Context()
+	normalizeRecursive(ctx, dst)
+	err := ValidateCtx(ctx, dst)
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		return err
+	}
+	for _, pf := range paramFields(reflect.TypeOf(dst)) {
+		if e, ok := errs[pf.errKey]; ok && pf.errKey != pf.name {
+			delete(errs, pf.errKey)
+			errs[pf.name] = e
+		}
+	}
+	return errs
+}
+
+// listParam reports whether field takes a list of values rather than one.
+func listParam(field reflect.Value) bool {
+	return field.Kind() == reflect.Slice && !field.Addr().Type().Implements(textUnmarshalerType)
+}
+
+// setParam converts values into field.
+func setParam(field reflect.Value, values []string) error {
+	if listParam(field) {
+		s := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := setScalar(s.Index(i), v); err != nil {
+				return err
+			}
+		}
+		field.Set(s)
+		return nil
+	}
+	return setScalar(field, values[0])
+}
+
+func setScalar(field reflect.Value, s string) error {
+	if field.Kind() == reflect.Ptr {
+		v := reflect.New(field.Type().Elem())
+		if err := setScalar(v.Elem(), s); err != nil {
+			return err
+		}
+		field.Set(v)
+		return nil
+	}
+	if tu, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported parameter type %s", field.Type())
+	}
+	return nil
+}
+
+// paramTypeName names t the way OpenAPI does, for error messages.
+func paramTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || (t.Kind() == reflect.Slice && !reflect.PointerTo(t).Implements(textUnmarshalerType)) {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return t.String()
+}
@@ -0,0 +1,165 @@
+package apivalidation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v "github.com/Gobd/apivalidation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindPaging struct {
+	Limit  int  `query:"limit"`
+	Offset *int `query:"offset"`
+}
+
+func (p *bindPaging) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.Limit, v.Min(1), v.Max(100)),
+	}
+}
+
+type bindParams struct {
+	bindPaging
+	ID      string    `path:"id"`
+	Tags    []string  `query:"tag"`
+	Tenant  string    `header:"X-Tenant"`
+	Session string    `cookie:"session"`
+	Since   time.Time `query:"since"`
+	Active  bool      `query:"active"`
+	IDs     []int     `header:"X-IDs"`
+	Body    string    `json:"body"`
+}
+
+func (p *bindParams) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.bindPaging),
+		v.Field(&p.ID, v.Required, v.Length(2, 10)),
+		v.Field(&p.Tenant, v.Required),
+	}
+}
+
+// serveBind routes target through a ServeMux so path values are populated.
+func serveBind(t *testing.T, target string, setup func(r *http.Request), fn func(r *http.Request)) {
+	t.Helper()
+	mux := http.NewServeMux()
+	called := false
+	mux.HandleFunc("GET /orders/{id}", func(_ http.ResponseWriter, r *http.Request) {
+		called = true
+		fn(r)
+	})
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if setup != nil {
+		setup(r)
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+	require.True(t, called)
+}
+
+func TestBind_AllLocations(t *testing.T) {
+	serveBind(t, "/orders/ab?limit=5&offset=2&tag=x&tag=y&since=2024-01-02T03:04:05Z&active=true", func(r *http.Request) {
+		r.Header.Set("X-Tenant", "acme")
+		r.Header.Set("X-IDs", "1,2,3")
+		r.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	}, func(r *http.Request) {
+		var p bindParams
+		require.NoError(t, v.BindAndValidate(r, &p))
+		assert.Equal(t, "ab", p.ID)
+		assert.Equal(t, 5, p.Limit)
+		require.NotNil(t, p.Offset)
+		assert.Equal(t, 2, *p.Offset)
+		assert.Equal(t, []string{"x", "y"}, p.Tags)
+		assert.Equal(t, "acme", p.Tenant)
+		assert.Equal(t, "s1", p.Session)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), p.Since)
+		assert.True(t, p.Active)
+		assert.Equal(t, []int{1, 2, 3}, p.IDs)
+	})
+}
+
+func TestBind_ScalarWithComma(t *testing.T) {
+	serveBind(t, "/orders/a,b", func(r *http.Request) {
+		r.Header.Set("X-Tenant", "hello, world")
+		r.AddCookie(&http.Cookie{Name: "session", Value: "s1,s2"})
+	}, func(r *http.Request) {
+		var p bindParams
+		require.NoError(t, v.Bind(r, &p))
+		assert.Equal(t, "a,b", p.ID)
+		assert.Equal(t, "hello, world", p.Tenant)
+		assert.Equal(t, "s1,s2", p.Session)
+	})
+}
+
+func TestBind_ConversionErrors(t *testing.T) {
+	serveBind(t, "/orders/ab?limit=ten&active=maybe", nil, func(r *http.Request) {
+		var p bindParams
+		err := v.Bind(r, &p)
+		var errs validation.Errors
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "must be a valid integer", errs["limit"].Error())
+		assert.Equal(t, "must be a valid boolean", errs["active"].Error())
+
+		var verr validation.Error
+		require.ErrorAs(t, errs["limit"], &verr)
+		assert.Equal(t, "bind_invalid", verr.Code())
+	})
+}
+
+func TestBindAndValidate_KeysByParamName(t *testing.T) {
+	serveBind(t, "/orders/a?limit=500", nil, func(r *http.Request) {
+		var p bindParams
+		fes := v.FieldErrors(v.BindAndValidate(r, &p))
+		pointers := make([]string, len(fes))
+		for i, fe := range fes {
+			pointers[i] = fe.Pointer
+		}
+		assert.Equal(t, []string{"/X-Tenant", "/id", "/limit"}, pointers)
+	})
+}
+
+func TestBind_NotStructPointer(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	var s string
+	assert.Error(t, v.Bind(r, &s))
+	assert.Error(t, v.Bind(r, bindParams{}))
+}
+
+func TestNewParameters(t *testing.T) {
+	params, err := v.NewParameters(bindParams{})
+	require.NoError(t, err)
+
+	byName := map[string]int{}
+	for i, p := range params {
+		byName[p.Value.Name] = i
+	}
+	require.Len(t, params, 9)
+	assert.NotContains(t, byName, "body")
+
+	limit := params[byName["limit"]].Value
+	assert.Equal(t, "query", limit.In)
+	assert.False(t, limit.Required)
+	require.NotNil(t, limit.Schema.Value.Min)
+	assert.Equal(t, float64(1), *limit.Schema.Value.Min)
+	require.NotNil(t, limit.Schema.Value.Max)
+	assert.Equal(t, float64(100), *limit.Schema.Value.Max)
+
+	id := params[byName["id"]].Value
+	assert.Equal(t, "path", id.In)
+	assert.True(t, id.Required)
+	assert.Equal(t, uint64(2), id.Schema.Value.MinLength)
+
+	tenant := params[byName["X-Tenant"]].Value
+	assert.Equal(t, "header", tenant.In)
+	assert.True(t, tenant.Required)
+
+	assert.Equal(t, "cookie", params[byName["session"]].Value.In)
+	assert.True(t, params[byName["tag"]].Value.Schema.Value.Type.Is("array"))
+
+	_, err = v.NewParameters("nope")
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package apivalidation
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"text/template"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Catalog holds message templates keyed by locale and error code.
+// Templates use [text/template] syntax over the error's params, the same
+// format as [validation.NewError] messages (e.g. "mindestens {{.min}}").
+//
+// Describe implementations use the catalog's default locale for the prose
+// they add to OpenAPI descriptions, so one catalog drives both error
+// messages and generated docs.
+type Catalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}
+
+// Messages is the catalog used by [ValidateCtx] and by the built-in rules'
+// Describe methods. Register translations at startup:
+//
+//	v.Messages.Add("de", map[string]string{
+//	    "validation_required": "darf nicht leer sein",
+//	})
+var Messages = NewCatalog()
+
+type localeKey struct{}
+
+// NewCatalog returns an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: map[string]map[string]string{}}
+}
+
+// Add registers message templates for locale, replacing existing templates
+// for the same codes.
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := c.messages[locale]
+	if m == nil {
+		m = map[string]string{}
+		c.messages[locale] = m
+	}
+	for code, msg := range messages {
+		m[code] = msg
+	}
+}
+
+// SetDefaultLocale sets the locale used when none is given, including for
+// schema descriptions.
+func (c *Catalog) SetDefaultLocale(locale string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultLocale = locale
+}
+
+// DefaultLocale returns the locale set by [Catalog.SetDefaultLocale].
+func (c *Catalog) DefaultLocale() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.defaultLocale
+}
+
+// Lookup returns the template for code in locale. A regional locale such as
+// "de-CH" falls back to its base language "de". An empty locale uses the
+// default locale.
+func (c *Catalog) Lookup(locale, code string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if locale == "" {
+		locale = c.defaultLocale
+	}
+	if msg, ok := c.messages[locale][code]; ok {
+		return msg, true
+	}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		msg, ok := c.messages[locale[:i]][code]
+		return msg, ok
+	}
+	return "", false
+}
+
+// Translate returns a copy of err with every [validation.Error] message
+// replaced by the catalog template for its code, recursing into
+// [ValidationErrors]. Errors without a matching template are left as is.
+func (c *Catalog) Translate(err error, locale string) error {
+	switch e := err.(type) {
+	case validation.Errors:
+		out := make(validation.Errors, len(e))
+		for k, v := range e {
+			out[k] = c.Translate(v, locale)
+		}
+		return out
+	case validation.Error:
+		if msg, ok := c.Lookup(locale, e.Code()); ok {
+			return e.SetMessage(msg)
+		}
+	}
+	return err
+}
+
+// Describe renders the template for code in the default locale, falling back
+// to fallback when the catalog has no entry. Rules use it for the prose they
+// add to schema descriptions.
+func (c *Catalog) Describe(code, fallback string, params map[string]any) string {
+	msg, ok := c.Lookup("", code)
+	if !ok {
+		msg = fallback
+	}
+	return render(code, msg, params)
+}
+
+// Format renders the template for code in locale with params. If the catalog
+// has no entry, fallback is returned unchanged.
+func (c *Catalog) Format(locale, code, fallback string, params map[string]any) string {
+	msg, ok := c.Lookup(locale, code)
+	if !ok {
+		return fallback
+	}
+	return render(code, msg, params)
+}
+
+func render(code, msg string, params map[string]any) string {
+	if !strings.Contains(msg, "{{") {
+		return msg
+	}
+	tmpl, err := template.New(code).Parse(msg)
+	if err != nil {
+		return msg
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return msg
+	}
+	return buf.String()
+}
+
+// WithLocale returns a context that makes [ValidateCtx] and the
+// decode-and-validate helpers translate errors into locale using [Messages].
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set by [WithLocale], or "".
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
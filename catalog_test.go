@@ -0,0 +1,83 @@
+package apivalidation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_Lookup(t *testing.T) {
+	c := v.NewCatalog()
+	c.Add("de", map[string]string{"validation_required": "darf nicht leer sein"})
+
+	msg, ok := c.Lookup("de", "validation_required")
+	assert.True(t, ok)
+	assert.Equal(t, "darf nicht leer sein", msg)
+
+	msg, ok = c.Lookup("de-CH", "validation_required")
+	assert.True(t, ok)
+	assert.Equal(t, "darf nicht leer sein", msg)
+
+	_, ok = c.Lookup("fr", "validation_required")
+	assert.False(t, ok)
+
+	_, ok = c.Lookup("", "validation_required")
+	assert.False(t, ok)
+	c.SetDefaultLocale("de")
+	_, ok = c.Lookup("", "validation_required")
+	assert.True(t, ok)
+}
+
+func TestCatalog_TranslateNested(t *testing.T) {
+	c := v.NewCatalog()
+	c.Add("de", map[string]string{
+		"validation_required":            "darf nicht leer sein",
+		"validation_length_out_of_range": "Länge muss zwischen {{.min}} und {{.max}} liegen",
+	})
+
+	p := valParent{Children: []valChild{{Name: ""}}}
+	err := c.Translate(v.Validate(&p), "de")
+	require.Error(t, err)
+	assert.Equal(t, "Children: (0: (Name: darf nicht leer sein.).); Title: darf nicht leer sein.", err.Error())
+
+	item := valItem{Name: "this name is far too long to pass the length rule of fifty runes"}
+	fes := v.FieldErrors(c.Translate(v.Validate(&item), "de"))
+	require.Len(t, fes, 1)
+	assert.Equal(t, "Länge muss zwischen 1 und 50 liegen", fes[0].Message)
+	assert.Equal(t, "validation_length_out_of_range", fes[0].Code)
+}
+
+func TestCatalog_TranslatePlainError(t *testing.T) {
+	err := errors.New("boom")
+	assert.Equal(t, err, v.NewCatalog().Translate(err, "de"))
+}
+
+func TestValidateCtx_Locale(t *testing.T) {
+	v.Messages.Add("xx-test", map[string]string{"validation_required": "xx required"})
+
+	item := valItem{}
+	err := v.ValidateCtx(v.WithLocale(context.Background(), "xx-test"), &item)
+	require.Error(t, err)
+	assert.Equal(t, "Name: xx required.", err.Error())
+
+	err = v.Validate(&item)
+	require.Error(t, err)
+	assert.Equal(t, "Name: cannot be blank.", err.Error())
+}
+
+func TestCatalog_Describe(t *testing.T) {
+	v.Messages.Add("yy-test", map[string]string{"describe_date_min": "ab {{.min}}"})
+	v.Messages.SetDefaultLocale("yy-test")
+	t.Cleanup(func() { v.Messages.SetDefaultLocale("") })
+
+	ref := &openapi3.SchemaRef{Value: openapi3.NewSchema()}
+	rule := v.Date(time.DateOnly).Min(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, rule.Describe("m", openapi3.NewSchema(), ref))
+	assert.Equal(t, "ab 2024-01-02", ref.Value.Description)
+}
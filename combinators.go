@@ -0,0 +1,192 @@
+package apivalidation
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type combinatorKind int
+
+const (
+	combineAny combinatorKind = iota
+	combineOne
+	combineAll
+	combineNot
+)
+
+// combinedRule applies rules according to kind.
+type combinedRule struct {
+	kind  combinatorKind
+	rules []Rule
+}
+
+// AnyOf returns a rule that passes when at least one of rules passes, and
+// documents them as anyOf subschemas. Group several rules into one
+// alternative with [AllOf]. Empty values pass. On failure it returns [ErrAnyOfInvalid] with the
+// "codes" and "errors" params listing each alternative's error.
+//
+//	v.Field(&o.Contact, v.AnyOf(v.Pattern(uuidPattern), v.Pattern(emailPattern)))
+func AnyOf(rules ...Rule) Rule {
+	return &combinedRule{kind: combineAny, rules: rules}
+}
+
+// OneOf returns a rule that passes when exactly one of rules passes, and
+// documents them as oneOf subschemas. Empty values pass, since most rules
+// skip them and would all match. On failure it returns
+// [ErrOneOfInvalid] with the "matched" param, the number of alternatives
+// that passed, and the "codes" and "errors" params of those that failed.
+func OneOf(rules ...Rule) Rule {
+	return &combinedRule{kind: combineOne, rules: rules}
+}
+
+// AllOf returns a rule that passes when all of rules pass, like listing
+// them in [Field]. It returns the first error. Its rules are documented on
+// the schema itself, so AllOf inside [AnyOf] or [OneOf] makes a single
+// alternative of several rules.
+func AllOf(rules ...Rule) Rule {
+	return &combinedRule{kind: combineAll, rules: rules}
+}
+
+// Not returns a rule that passes when rule fails, and documents rule as a
+// not subschema. Empty values pass. On failure it returns [ErrNotAllowed].
+// A rule that describes nothing, such as [By] without a description, is
+// still validated but leaves not out of the schema, since an empty not
+// schema would match nothing.
+//
+//	v.Field(&u.Username, v.Not(v.In("admin", "root")))
+func Not(rule Rule) Rule {
+	return &combinedRule{kind: combineNot, rules: []Rule{rule}}
+}
+
+func (r *combinedRule) Validate(value any) error {
+	if r.kind != combineAll && validation.IsEmpty(value) {
+		return nil
+	}
+	if r.kind == combineNot {
+		err := r.rules[0].Validate(value)
+		if err == nil {
+			return ErrNotAllowed
+		}
+		if _, ok := err.(validation.InternalError); ok {
+			return err
+		}
+		return nil
+	}
+
+	var failed []error
+	for _, rule := range r.rules {
+		err := rule.Validate(value)
+		if err == nil {
+			if r.kind == combineAny {
+				return nil
+			}
+			continue
+		}
+		if _, ok := err.(validation.InternalError); ok {
+			return err
+		}
+		if r.kind == combineAll {
+			return err
+		}
+		failed = append(failed, err)
+	}
+	switch r.kind {
+	case combineAny:
+		if len(failed) == 0 {
+			return nil
+		}
+		return ErrAnyOfInvalid.SetParams(alternativeParams(failed, map[string]any{}))
+	case combineOne:
+		matched := len(r.rules) - len(failed)
+		if matched == 1 {
+			return nil
+		}
+		return ErrOneOfInvalid.SetParams(alternativeParams(failed, map[string]any{"matched": matched}))
+	}
+	return nil
+}
+
+// alternativeParams adds the codes and messages of the failed alternatives
+// to params.
+func alternativeParams(failed []error, params map[string]any) map[string]any {
+	codes := make([]string, len(failed))
+	messages := make([]string, len(failed))
+	for i, err := range failed {
+		if verr, ok := err.(validation.Error); ok {
+			codes[i] = verr.Code()
+		}
+		messages[i] = err.Error()
+	}
+	params["codes"] = codes
+	params["errors"] = messages
+	return params
+}
+
+func (r *combinedRule) bindFields(name func(fieldPtr any) string) {
+	for _, rule := range r.rules {
+		bindRule(rule, name)
+	}
+}
+
+// Describe documents the rules as anyOf, oneOf or not subschemas of the
+// property. Each rule is described on its own scratch schema, with the
+// property's type so that type-dependent rules such as [Length] pick the
+// right keywords. Rules that describe nothing are left out of not.
+func (r *combinedRule) Describe(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	return r.describe(name, schema, ref, options{})
+}
+
+func (r *combinedRule) describe31(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	return r.describe(name, schema, ref, options{openapi31: true})
+}
+
+// describe is Describe, describing the rules with o.
+func (r *combinedRule) describe(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef, o options) error {
+	if r.kind == combineAll {
+		for _, rule := range r.rules {
+			if err := describeRule(rule, o, name, schema, ref); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	subs := make(openapi3.SchemaRefs, 0, len(r.rules))
+	for _, rule := range r.rules {
+		sub, err := describeSubschema(name, rule, ref.Value.Type, o)
+		if err != nil {
+			return err
+		}
+		subs = append(subs, sub)
+	}
+	switch r.kind {
+	case combineAny:
+		ref.Value.AnyOf = append(ref.Value.AnyOf, subs...)
+	case combineOne:
+		ref.Value.OneOf = append(ref.Value.OneOf, subs...)
+	case combineNot:
+		if !reflect.ValueOf(*subs[0].Value).IsZero() {
+			ref.Value.Not = subs[0]
+		}
+	}
+	return nil
+}
+
+// describeSubschema describes rule with o on a scratch schema of type typ,
+// and returns it without the type.
+func describeSubschema(name string, rule Rule, typ *openapi3.Types, o options) (*openapi3.SchemaRef, error) {
+	sub := &openapi3.SchemaRef{Value: openapi3.NewSchema()}
+	if typ != nil {
+		types := slices.Clone(typ.Slice())
+		sub.Value.Type = (*openapi3.Types)(&types)
+	}
+	if err := describeRule(rule, o, name, nil, sub); err != nil {
+		return nil, err
+	}
+	if typ != nil && sub.Value.Type != nil && slices.Equal(sub.Value.Type.Slice(), typ.Slice()) {
+		sub.Value.Type = nil
+	}
+	return sub, nil
+}
@@ -0,0 +1,104 @@
+package apivalidation_test
+
+import (
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type combinedUser struct {
+	Contact  string   `json:"contact"`
+	Username string   `json:"username"`
+	Code     string   `json:"code"`
+	Tags     []string `json:"tags"`
+}
+
+const (
+	combinedUUID  = `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
+	combinedEmail = `^[^@\s]+@[^@\s]+$`
+)
+
+func (u *combinedUser) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&u.Contact, v.AnyOf(v.Pattern(combinedUUID), v.Pattern(combinedEmail))),
+		v.Field(&u.Username, v.Not(v.In("admin", "root"))),
+		v.Field(&u.Code, v.OneOf(v.Pattern(`^[A-Z]+$`), v.AllOf(v.Pattern(`^\d+$`), v.Length(4, 4)))),
+		v.Field(&u.Tags, v.AnyOf(v.Length(0, 2), v.Length(5, 5))),
+	}
+}
+
+func TestCombinators_Validate(t *testing.T) {
+	valid := combinedUser{Contact: "a@example.com", Username: "bob", Code: "1234"}
+	require.NoError(t, v.Validate(&valid))
+
+	valid.Contact = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	valid.Code = "ABC"
+	require.NoError(t, v.Validate(&valid))
+
+	errs := v.FieldErrors(v.Validate(&combinedUser{Contact: "nope", Username: "root", Code: "12345", Tags: []string{"a", "b", "c"}}))
+	require.Len(t, errs, 4)
+
+	assert.Equal(t, "/code", errs[0].Pointer)
+	assert.Equal(t, v.ErrOneOfInvalid.Code(), errs[0].Code)
+	assert.Equal(t, 0, errs[0].Params["matched"])
+	assert.Equal(t, []string{validation.ErrMatchInvalid.Code(), "validation_length_invalid"}, errs[0].Params["codes"])
+
+	assert.Equal(t, "/contact", errs[1].Pointer)
+	assert.Equal(t, v.ErrAnyOfInvalid.Code(), errs[1].Code)
+	assert.Equal(t, []string{validation.ErrMatchInvalid.Code(), validation.ErrMatchInvalid.Code()}, errs[1].Params["codes"])
+	assert.Len(t, errs[1].Params["errors"], 2)
+
+	assert.Equal(t, "/tags", errs[2].Pointer)
+	assert.Equal(t, "/username", errs[3].Pointer)
+	assert.Equal(t, v.ErrNotAllowed.Code(), errs[3].Code)
+}
+
+func TestCombinators_OneOfMatchesBoth(t *testing.T) {
+	err := v.OneOf(v.Length(1, 5), v.Pattern(`^a`)).Validate("abc")
+	require.Error(t, err)
+	assert.Equal(t, 2, err.(validation.Error).Params()["matched"])
+	assert.NoError(t, v.Not(v.In("admin")).Validate(""), "empty values pass")
+}
+
+func TestCombinators_EmptyOptional(t *testing.T) {
+	require.NoError(t, v.Validate(&combinedUser{}))
+	assert.NoError(t, v.OneOf(v.Length(1, 5), v.Pattern(`^a`)).Validate(""))
+	assert.NoError(t, v.AnyOf(v.In("a")).Validate(nil))
+
+	errs := v.FieldErrors(v.Validate(&combinedUser{Code: "ab"}))
+	require.Len(t, errs, 1)
+	assert.Equal(t, v.ErrOneOfInvalid.Code(), errs[0].Code)
+}
+
+func TestCombinators_Schema(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(combinedUser{})
+	require.NoError(t, err)
+
+	contact := schema.Value.Properties["contact"].Value
+	require.Len(t, contact.AnyOf, 2)
+	assert.Equal(t, combinedUUID, contact.AnyOf[0].Value.Pattern)
+	assert.Equal(t, combinedEmail, contact.AnyOf[1].Value.Pattern)
+	assert.Nil(t, contact.AnyOf[0].Value.Type, "the type stays on the property")
+
+	username := schema.Value.Properties["username"].Value
+	require.NotNil(t, username.Not)
+	assert.Equal(t, []any{"admin", "root"}, username.Not.Value.Enum)
+
+	code := schema.Value.Properties["code"].Value
+	require.Len(t, code.OneOf, 2)
+	assert.Equal(t, `^[A-Z]+$`, code.OneOf[0].Value.Pattern)
+	assert.Equal(t, `^\d+$`, code.OneOf[1].Value.Pattern)
+	assert.Equal(t, uint64(4), code.OneOf[1].Value.MinLength)
+
+	tags := schema.Value.Properties["tags"].Value
+	require.Len(t, tags.AnyOf, 2)
+	assert.Equal(t, uint64(5), tags.AnyOf[1].Value.MinItems, "Length sees the array type")
+
+	errs := v.FieldErrors(v.ValidateJSON(schema, []byte(`{"contact":"a@example.com","username":"root","code":"ABC"}`)))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/username", errs[0].Pointer)
+	assert.Equal(t, v.ErrNotAllowed.Code(), errs[0].Code)
+}
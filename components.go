@@ -0,0 +1,330 @@
+package apivalidation
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// componentsPrefix is the JSON Pointer prefix of component schema references.
+const componentsPrefix = "#/components/schemas/"
+
+// Components is a registry of named schemas, backed by a components.schemas
+// map. With [WithComponents], schema generation emits each named type that
+// implements [Ruler], [ContextRuler] or [ValueRuler] once into the registry
+// and references it with $ref wherever it appears, including the top-level
+// value. A Components is safe for concurrent use.
+//
+// Names are the Go type name. Generic instantiations join the short names of
+// their type arguments with underscores (Page[billing.Order] is
+// "Page_Order"). A type whose name is already taken by a type from another
+// package is qualified with its package name ("billing.Order"), then with
+// its full import path if that is taken too; the type arguments of generic
+// instantiations are qualified along with it ("billing.Page_shop.Order").
+// Registering a type when all of these names are already in the schemas map
+// panics. Schemas generated with [Strict] or [Partial] differ from the plain
+// ones, so struct types get a "Strict" or "Partial" suffix for them.
+type Components struct {
+	mu      sync.Mutex
+	schemas openapi3.Schemas
+	names   map[componentKey]string
+	taken   map[string]componentKey
+}
+
+type componentKey struct {
+	typ     reflect.Type
+	variant string
+}
+
+// NewComponents returns a registry adding schemas to the given map, usually
+// doc.Components.Schemas. Existing entries are never overwritten.
+func NewComponents(schemas openapi3.Schemas) *Components {
+	return &Components{
+		schemas: schemas,
+		names:   map[componentKey]string{},
+		taken:   map[string]componentKey{},
+	}
+}
+
+// WithComponents registers named Ruler and ValueRuler types in c during
+// schema generation and references them with $ref (see [Components]).
+func WithComponents(c *Components) Option {
+	return func(o *options) { o.components = c }
+}
+
+// Schemas returns the map the registry adds schemas to.
+func (c *Components) Schemas() openapi3.Schemas {
+	return c.schemas
+}
+
+// register adds schema as the component for t, unless one is already
+// registered, and returns a reference to the component.
+func (c *Components) register(t reflect.Type, variant string, schema *openapi3.Schema) *openapi3.SchemaRef {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := componentKey{typ: t, variant: variant}
+	name, ok := c.names[key]
+	if !ok {
+		name = c.nameFor(key)
+		c.names[key] = name
+		c.taken[name] = key
+	}
+	existing, ok := c.schemas[name]
+	if !ok {
+		existing = &openapi3.SchemaRef{Value: schema}
+		c.schemas[name] = existing
+	}
+	return &openapi3.SchemaRef{Ref: componentsPrefix + name, Value: existing.Value}
+}
+
+// nameFor returns a free component name for key: the short name, then the
+// name qualified with package names, then with import paths. Callers hold
+// c.mu.
+func (c *Components) nameFor(key componentKey) string {
+	t := key.typ
+	candidates := []string{
+		typeName(t.Name()) + key.variant,
+		path.Base(t.PkgPath()) + "." + typeNameIn(t.Name(), true) + key.variant,
+		pathName(t.PkgPath()) + "." + fullTypeName(t.Name()) + key.variant,
+	}
+	for _, name := range candidates {
+		if _, taken := c.taken[name]; !taken {
+			if _, exists := c.schemas[name]; !exists {
+				return name
+			}
+		}
+	}
+	// Import paths are unique, so only names added to the schemas map by
+	// other means can get here. Reusing one would reference the wrong schema.
+	panic(fmt.Sprintf("apivalidation: no free component name for %s (tried %s)", t, strings.Join(candidates, ", ")))
+}
+
+// typeName turns a reflect type name into a component name, replacing the
+// type arguments of generic instantiations by their short names:
+// "Page[example.com/billing.Order]" becomes "Page_Order".
+func typeName(name string) string {
+	return typeNameIn(name, false)
+}
+
+// typeNameIn is like typeName, but with qualify the type arguments keep
+// their package name: "Page[example.com/billing.Order]" becomes
+// "Page_billing.Order".
+func typeNameIn(name string, qualify bool) string {
+	base, args, ok := strings.Cut(name, "[")
+	if !ok {
+		return name
+	}
+	args = strings.TrimSuffix(args, "]")
+	parts := []string{base}
+	depth, start := 0, 0
+	for i, r := range args {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, typeArgName(args[start:i], qualify))
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, typeArgName(args[start:], qualify))
+	return strings.Join(parts, "_")
+}
+
+// typeArgName strips the package path from a type argument, keeping the
+// package name with qualify, and spells composite types out ("[]*pkg.Item"
+// becomes "ItemList").
+func typeArgName(arg string, qualify bool) string {
+	arg = strings.TrimSpace(arg)
+	switch {
+	case strings.HasPrefix(arg, "*"):
+		return typeArgName(arg[1:], qualify)
+	case strings.HasPrefix(arg, "[]"):
+		return typeArgName(arg[2:], qualify) + "List"
+	case strings.HasPrefix(arg, "map["):
+		return "Map"
+	}
+	generic, _, _ := strings.Cut(arg, "[")
+	if i := strings.LastIndex(generic, "/"); i >= 0 {
+		arg = arg[i+1:]
+	}
+	if pkg, short, ok := strings.Cut(arg, "."); ok {
+		arg = short
+		if qualify {
+			return pkg + "." + typeNameIn(arg, qualify)
+		}
+	}
+	return typeNameIn(arg, qualify)
+}
+
+// fullTypeName spells out a reflect type name with the import paths of its
+// type arguments, keeping pointers and the key types of maps apart:
+// "Page[*example.com/billing.Order]" becomes
+// "Page_Ptr_example.com_billing.Order".
+func fullTypeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSuffix(name, "]") {
+		switch {
+		case r == '*':
+			b.WriteString("Ptr_")
+		case r == '.' || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// pathName turns an import path into a component name prefix.
+func pathName(pkgPath string) string {
+	return strings.NewReplacer("/", "_", ".", "_").Replace(pkgPath)
+}
+
+// isComponentType reports whether t gets its own component schema: a named
+// type carrying rules.
+func isComponentType(t reflect.Type) bool {
+	if t.Name() == "" || t.PkgPath() == "" || t == reflect.TypeFor[time.Time]() || t.Kind() == reflect.Interface {
+		return false
+	}
+	for _, it := range []reflect.Type{t, reflect.PointerTo(t)} {
+		if it.Implements(rulerType) || it.Implements(contextRulerType) || it.Implements(valueRulerType) {
+			return true
+		}
+	}
+	return false
+}
+
+// componentRecorder collects the component schemas of one schema
+// generation. Each node is the schema generated for a component type; the
+// snapshot taken when the node was described is registered, since the
+// parent's field rules may still change the node afterwards.
+type componentRecorder struct {
+	c       *Components
+	variant string
+	nodes   map[*openapi3.Schema]recordedComponent
+}
+
+type recordedComponent struct {
+	snapshot *openapi3.Schema
+	ref      *openapi3.SchemaRef
+}
+
+func newComponentRecorder(c *Components, o options) *componentRecorder {
+	var variant string
+	if o.strict {
+		variant += "Strict"
+	}
+	if o.partial {
+		variant += "Partial"
+	}
+	return &componentRecorder{c: c, variant: variant, nodes: map[*openapi3.Schema]recordedComponent{}}
+}
+
+// record registers the fully described schema of t.
+func (r *componentRecorder) record(t reflect.Type, schema *openapi3.Schema) {
+	if isComponentType(t) {
+		r.add(t, schema)
+	}
+}
+
+// add registers the fully described schema of named type t.
+func (r *componentRecorder) add(t reflect.Type, schema *openapi3.Schema) {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return
+	}
+	variant := ""
+	if t.Kind() == reflect.Struct {
+		variant = r.variant
+	}
+	snapshot := *schema
+	snapshot.Nullable = false
+	r.nodes[schema] = recordedComponent{snapshot: &snapshot, ref: r.c.register(t, variant, &snapshot)}
+}
+
+// replace rewrites every reference to a recorded node in the tree under
+// root into a $ref to its component. Nodes changed by field rules of their
+// parent, or made nullable, become an allOf of the $ref with the changes
+// alongside.
+func (r *componentRecorder) replace(root *openapi3.SchemaRef) {
+	var refs []*openapi3.SchemaRef
+	walkSchemaRefs(root, map[*openapi3.SchemaRef]bool{}, func(ref *openapi3.SchemaRef) {
+		if _, ok := r.nodes[ref.Value]; ok {
+			refs = append(refs, ref)
+		}
+	})
+	// Compute all overlays first: nodes and their snapshots share children.
+	values := make([]*openapi3.Schema, len(refs))
+	for i, ref := range refs {
+		values[i] = schemaOverlay(ref.Value, r.nodes[ref.Value].snapshot)
+	}
+	for i, ref := range refs {
+		component := r.nodes[ref.Value].ref
+		if values[i] == nil {
+			ref.Ref, ref.Value = component.Ref, component.Value
+			continue
+		}
+		values[i].AllOf = append(openapi3.SchemaRefs{{Ref: component.Ref, Value: component.Value}}, values[i].AllOf...)
+		ref.Ref, ref.Value = "", values[i]
+	}
+}
+
+// schemaOverlay returns the fields of node that differ from base, or nil if
+// there are none. Text appended to base's description is kept on its own.
+func schemaOverlay(node, base *openapi3.Schema) *openapi3.Schema {
+	if base.Description != "" && strings.HasPrefix(node.Description, base.Description) {
+		n := *node
+		n.Description = strings.TrimSpace(strings.TrimPrefix(node.Description, base.Description))
+		if n.Description == "" {
+			n.Description = base.Description
+		}
+		node = &n
+	}
+	overlay := &openapi3.Schema{}
+	nv, bv, ov := reflect.ValueOf(node).Elem(), reflect.ValueOf(base).Elem(), reflect.ValueOf(overlay).Elem()
+	changed := false
+	for i := range nv.NumField() {
+		if !nv.Type().Field(i).IsExported() || reflect.DeepEqual(nv.Field(i).Interface(), bv.Field(i).Interface()) {
+			continue
+		}
+		ov.Field(i).Set(nv.Field(i))
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return overlay
+}
+
+// walkSchemaRefs calls fn for each schema reference under ref, parents
+// before children.
+func walkSchemaRefs(ref *openapi3.SchemaRef, seen map[*openapi3.SchemaRef]bool, fn func(*openapi3.SchemaRef)) {
+	if ref == nil || seen[ref] {
+		return
+	}
+	seen[ref] = true
+	fn(ref)
+	s := ref.Value
+	if s == nil {
+		return
+	}
+	for _, child := range s.Properties {
+		walkSchemaRefs(child, seen, fn)
+	}
+	for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, child := range refs {
+			walkSchemaRefs(child, seen, fn)
+		}
+	}
+	walkSchemaRefs(s.Items, seen, fn)
+	walkSchemaRefs(s.Not, seen, fn)
+	walkSchemaRefs(s.AdditionalProperties.Schema, seen, fn)
+}
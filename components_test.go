@@ -0,0 +1,78 @@
+package apivalidation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+type Duration struct{}
+
+func TestComponents_NameCollision(t *testing.T) {
+	c := NewComponents(openapi3.Schemas{})
+	local := c.register(reflect.TypeFor[Duration](), "", &openapi3.Schema{})
+	other := c.register(reflect.TypeFor[time.Duration](), "", &openapi3.Schema{})
+	again := c.register(reflect.TypeFor[Duration](), "", &openapi3.Schema{})
+
+	assert.Equal(t, "#/components/schemas/Duration", local.Ref)
+	assert.Equal(t, "#/components/schemas/time.Duration", other.Ref)
+	assert.Equal(t, local.Ref, again.Ref)
+	assert.Len(t, c.Schemas(), 2)
+}
+
+type Page[T any] struct{}
+
+func TestComponents_GenericNameCollision(t *testing.T) {
+	c := NewComponents(openapi3.Schemas{})
+	local := c.register(reflect.TypeFor[Page[Duration]](), "", &openapi3.Schema{})
+	other := c.register(reflect.TypeFor[Page[time.Duration]](), "", &openapi3.Schema{})
+	ptr := c.register(reflect.TypeFor[Page[*Duration]](), "", &openapi3.Schema{})
+	again := c.register(reflect.TypeFor[Page[time.Duration]](), "", &openapi3.Schema{})
+
+	assert.Equal(t, "#/components/schemas/Page_Duration", local.Ref)
+	assert.Equal(t, "#/components/schemas/apivalidation.Page_time.Duration", other.Ref)
+	assert.Equal(t, "#/components/schemas/apivalidation.Page_apivalidation.Duration", ptr.Ref)
+	assert.Equal(t, other.Ref, again.Ref)
+	assert.Len(t, c.Schemas(), 3)
+
+	full := c.register(reflect.TypeFor[Page[**Duration]](), "", &openapi3.Schema{})
+	assert.Equal(t, "#/components/schemas/github_com_Gobd_apivalidation.Page_Ptr_Ptr_github.com_Gobd_apivalidation.Duration", full.Ref)
+}
+
+func TestComponents_NoFreeName(t *testing.T) {
+	c := NewComponents(openapi3.Schemas{
+		"Page_Duration": {}, "apivalidation.Page_time.Duration": {},
+		"github_com_Gobd_apivalidation.Page_time.Duration": {},
+	})
+	assert.Panics(t, func() {
+		c.register(reflect.TypeFor[Page[time.Duration]](), "", &openapi3.Schema{})
+	})
+}
+
+func TestTypeName(t *testing.T) {
+	tests := map[string]string{
+		"Order":                                        "Order",
+		"Page[example.com/billing.Order]":              "Page_Order",
+		"Pair[int,*example.com/billing.Order]":         "Pair_int_Order",
+		"Page[[]example.com/billing.Order]":            "Page_OrderList",
+		"Page[example.com/x.Page[example.com/y.Item]]": "Page_Page_Item",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, typeName(in), in)
+	}
+}
+
+func TestTypeNameIn(t *testing.T) {
+	tests := map[string]string{
+		"Order":                                        "Order",
+		"Page[example.com/billing.Order]":              "Page_billing.Order",
+		"Pair[int,*example.com/billing.Order]":         "Pair_int_billing.Order",
+		"Page[example.com/x.Page[example.com/y.Item]]": "Page_x.Page_y.Item",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, typeNameIn(in, true), in)
+	}
+}
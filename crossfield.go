@@ -0,0 +1,401 @@
+package apivalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// crossFieldRule is implemented by rules that refer to other fields of the
+// same struct. Before validating or describing, bindFields is called with a
+// function that resolves a field pointer to the field's name (its error key
+// when validating, its JSON name when generating schemas).
+type crossFieldRule interface {
+	bindFields(name func(fieldPtr any) string)
+}
+
+// structLevelRule is a rule that validates the whole struct rather than a
+// single field value. It is returned by [Struct] and [MutuallyExclusive].
+type structLevelRule interface {
+	Rule
+	validateStruct(ctx context.Context) error
+}
+
+// Struct returns a struct-level rule to list alongside [Field] in Rules().
+// f can inspect any fields of the receiver; use [ErrorFor] to report the
+// error against specific fields. Errors not attributed to a field are keyed
+// by "" (the struct itself). desc is appended to the struct's schema
+// description.
+//
+//	func (o *Booking) Rules() []*v.FieldRules {
+//	    return []*v.FieldRules{
+//	        v.Field(&o.Start, v.Required),
+//	        v.Struct(func(ctx context.Context) error {
+//	            if o.Nights > 14 && !o.Extended {
+//	                return v.ErrorFor(errors.New("stays over 14 nights must be extended"), &o.Nights)
+//	            }
+//	            return nil
+//	        }, "Stays over 14 nights must be extended."),
+//	    }
+//	}
+func Struct(f func(ctx context.Context) error, desc string) *FieldRules {
+	return &FieldRules{rules: []Rule{&structRule{f: f, desc: desc}}}
+}
+
+type structRule struct {
+	f    func(ctx context.Context) error
+	desc string
+}
+
+func (r *structRule) Validate(any) error {
+	return r.f(context.Background())
+}
+
+func (r *structRule) validateStruct(ctx context.Context) error {
+	return r.f(ctx)
+}
+
+func (r *structRule) Describe(_ string, schema *openapi3.Schema, _ *openapi3.SchemaRef) error {
+	if r.desc == "" {
+		return nil
+	}
+	if schema.Description != "" && !strings.HasSuffix(schema.Description, " ") {
+		schema.Description += " "
+	}
+	schema.Description += r.desc
+	return nil
+}
+
+// ErrorFor attributes err to the fields the pointers refer to, so a
+// [Struct] rule reports under those fields' keys instead of the struct's.
+// Returns nil if err is nil.
+func ErrorFor(err error, fieldPtrs ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{err: err, fieldPtrs: fieldPtrs}
+}
+
+type fieldsError struct {
+	err       error
+	fieldPtrs []any
+}
+
+func (e *fieldsError) Error() string { return e.err.Error() }
+
+func (e *fieldsError) Unwrap() error { return e.err }
+
+// applyStructRules runs the struct-level rules in fields and adds their
+// errors to errs. Field errors already in errs are kept.
+func applyStructRules(ctx context.Context, fields []*FieldRules, name func(fieldPtr any) string, errs validation.Errors) {
+	for _, fr := range fields {
+		if fr.fieldPtr != nil {
+			continue
+		}
+		for _, rule := range fr.rules {
+			bindRule(rule, name)
+			var err error
+			if sr, ok := rule.(structLevelRule); ok {
+				err = sr.validateStruct(ctx)
+			} else {
+				err = rule.Validate(nil)
+			}
+			if err == nil {
+				continue
+			}
+			var fe *fieldsError
+			if errors.As(err, &fe) {
+				for _, p := range fe.fieldPtrs {
+					if key := name(p); key != "" && errs[key] == nil {
+						errs[key] = fe.err
+					}
+				}
+				continue
+			}
+			if errs[""] == nil {
+				errs[""] = err
+			}
+		}
+	}
+}
+
+// bindRule binds field names into rule if it refers to other fields.
+func bindRule(rule Rule, name func(fieldPtr any) string) {
+	if cf, ok := rule.(crossFieldRule); ok {
+		cf.bindFields(name)
+	}
+}
+
+// fieldRef is a reference to another field of the same struct, resolved to
+// its name by bindFields.
+type fieldRef struct {
+	ptr  any
+	name string
+}
+
+func (f *fieldRef) bind(name func(fieldPtr any) string) {
+	f.name = name(f.ptr)
+}
+
+// value returns the referenced field's value, dereferencing pointers.
+func (f *fieldRef) value() any {
+	v, _ := validation.Indirect(reflect.ValueOf(f.ptr).Elem().Interface())
+	return v
+}
+
+func (f *fieldRef) empty() bool {
+	return validation.IsEmpty(reflect.ValueOf(f.ptr).Elem().Interface())
+}
+
+// RequiredIf returns a rule that requires the field when the field other
+// points to equals value. It is documented as x-requiredIf on the parent
+// schema, mapping the field to {other: value}, and as an allOf entry of the
+// form {"if": {"properties": {other: {"const": value}}, "required": [other]},
+// "then": {"required": [field]}} with [OpenAPI31].
+//
+//	v.Field(&o.CardNumber, v.RequiredIf(&o.Method, "card"))
+func RequiredIf[T comparable](other *T, value T) Rule {
+	return &requiredIfRule{other: fieldRef{ptr: other}, value: value, match: func() bool { return *other == value }}
+}
+
+type requiredIfRule struct {
+	other fieldRef
+	value any
+	match func() bool
+}
+
+func (r *requiredIfRule) bindFields(name func(fieldPtr any) string) { r.other.bind(name) }
+
+func (r *requiredIfRule) Validate(value any) error {
+	if !r.match() || !validation.IsEmpty(value) {
+		return nil
+	}
+	return ErrRequiredIf.SetParams(map[string]any{"field": r.other.name, "value": r.value})
+}
+
+func (r *requiredIfRule) Describe(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
+		ref.Value.Description += " "
+	}
+	ref.Value.Description += Messages.Describe("describe_required_if", "Required when {{.field}} is {{.value}}.",
+		map[string]any{"field": r.other.name, "value": r.value})
+	if r.other.name == "" || name == "" {
+		return nil
+	}
+	conds, _ := schema.Extensions["x-requiredIf"].(map[string]map[string]any)
+	if conds == nil {
+		conds = map[string]map[string]any{}
+		setExtension(schema, "x-requiredIf", conds)
+	}
+	if conds[name] == nil {
+		conds[name] = map[string]any{}
+	}
+	conds[name][r.other.name] = r.value
+	return nil
+}
+
+func (r *requiredIfRule) describe31(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	if schema == nil {
+		return r.Describe(name, openapi3.NewSchema(), ref)
+	}
+	if r.other.name == "" || name == "" {
+		return r.Describe(name, schema, ref)
+	}
+	schema.AllOf = append(schema.AllOf, &openapi3.SchemaRef{Value: &openapi3.Schema{Extensions: map[string]any{
+		"if":   ifFieldEquals(r.other.name, r.value),
+		"then": map[string]any{"required": []string{name}},
+	}}})
+	return nil
+}
+
+// RequiredWith returns a rule that requires the field when the field other
+// points to is not empty. It is documented as x-dependentRequired on the
+// parent schema.
+//
+//	v.Field(&o.Zip, v.RequiredWith(&o.Street))
+func RequiredWith[T any](other *T) Rule {
+	return &requiredWithRule{other: fieldRef{ptr: other}}
+}
+
+type requiredWithRule struct {
+	other fieldRef
+}
+
+func (r *requiredWithRule) bindFields(name func(fieldPtr any) string) { r.other.bind(name) }
+
+func (r *requiredWithRule) Validate(value any) error {
+	if r.other.empty() || !validation.IsEmpty(value) {
+		return nil
+	}
+	return ErrRequiredWith.SetParams(map[string]any{"field": r.other.name})
+}
+
+func (r *requiredWithRule) Describe(name string, schema *openapi3.Schema, _ *openapi3.SchemaRef) error {
+	if r.other.name == "" {
+		return nil
+	}
+	if schema.Extensions == nil {
+		schema.Extensions = map[string]any{}
+	}
+	deps, _ := schema.Extensions["x-dependentRequired"].(map[string][]string)
+	if deps == nil {
+		deps = map[string][]string{}
+		schema.Extensions["x-dependentRequired"] = deps
+	}
+	deps[r.other.name] = append(deps[r.other.name], name)
+	return nil
+}
+
+// GreaterThanField returns a rule that checks the field is greater than the
+// field other points to. Numbers, strings and [time.Time] are supported;
+// the rule passes when either value is empty.
+//
+//	v.Field(&o.End, v.GreaterThanField(&o.Start))
+func GreaterThanField[T any](other *T) Rule {
+	return &greaterThanFieldRule{other: fieldRef{ptr: other}}
+}
+
+type greaterThanFieldRule struct {
+	other fieldRef
+}
+
+func (r *greaterThanFieldRule) bindFields(name func(fieldPtr any) string) { r.other.bind(name) }
+
+func (r *greaterThanFieldRule) Validate(value any) error {
+	if validation.IsEmpty(value) || r.other.empty() {
+		return nil
+	}
+	value, _ = validation.Indirect(value)
+	other := r.other.value()
+	cmp, ok := compareValues(value, other)
+	if !ok {
+		return ErrTypeMismatch.SetParams(map[string]any{
+			"type": fmt.Sprintf("%T", value),
+			"want": fmt.Sprintf("%T", other),
+		})
+	}
+	if cmp > 0 {
+		return nil
+	}
+	return ErrGreaterThanField.SetParams(map[string]any{"field": r.other.name, "actual": value, "other": other})
+}
+
+func (r *greaterThanFieldRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
+		ref.Value.Description += " "
+	}
+	ref.Value.Description += Messages.Describe("describe_greater_than_field", "Must be greater than {{.field}}.",
+		map[string]any{"field": r.other.name})
+	return nil
+}
+
+// compareValues compares two numbers, strings or times. ok is false when
+// the values cannot be compared.
+func compareValues(a, b any) (cmp int, ok bool) {
+	if at, isTime := a.(time.Time); isTime {
+		bt, isTime := b.(time.Time)
+		if !isTime {
+			return 0, false
+		}
+		return at.Compare(bt), true
+	}
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case av.CanInt() && bv.CanInt():
+		return cmpOrdered(av.Int(), bv.Int()), true
+	case av.CanUint() && bv.CanUint():
+		return cmpOrdered(av.Uint(), bv.Uint()), true
+	case (av.CanInt() || av.CanUint() || av.CanFloat()) && (bv.CanInt() || bv.CanUint() || bv.CanFloat()):
+		return cmpOrdered(toFloat(av), toFloat(bv)), true
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return strings.Compare(av.String(), bv.String()), true
+	}
+	return 0, false
+}
+
+func cmpOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// MutuallyExclusive returns a struct-level rule that allows at most one of
+// the fields to be set (non-empty). The error is reported against each set
+// field. It is documented as allOf entries of the form
+// {"not": {"required": [a, b]}} on the parent schema.
+//
+//	v.MutuallyExclusive(&o.Card, &o.ACH)
+func MutuallyExclusive(fieldPtrs ...any) *FieldRules {
+	r := &mutuallyExclusiveRule{fields: make([]fieldRef, len(fieldPtrs))}
+	for i, p := range fieldPtrs {
+		r.fields[i] = fieldRef{ptr: p}
+	}
+	return &FieldRules{rules: []Rule{r}}
+}
+
+type mutuallyExclusiveRule struct {
+	fields []fieldRef
+}
+
+func (r *mutuallyExclusiveRule) bindFields(name func(fieldPtr any) string) {
+	for i := range r.fields {
+		r.fields[i].bind(name)
+	}
+}
+
+func (r *mutuallyExclusiveRule) names() []string {
+	names := make([]string, len(r.fields))
+	for i, f := range r.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+func (r *mutuallyExclusiveRule) Validate(any) error {
+	return r.validateStruct(context.Background())
+}
+
+func (r *mutuallyExclusiveRule) validateStruct(context.Context) error {
+	var set []any
+	for _, f := range r.fields {
+		if !f.empty() {
+			set = append(set, f.ptr)
+		}
+	}
+	if len(set) < 2 {
+		return nil
+	}
+	return ErrorFor(ErrMutuallyExclusive.SetParams(map[string]any{"fields": r.names()}), set...)
+}
+
+func (r *mutuallyExclusiveRule) Describe(_ string, schema *openapi3.Schema, _ *openapi3.SchemaRef) error {
+	names := r.names()
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			schema.AllOf = append(schema.AllOf, &openapi3.SchemaRef{Value: &openapi3.Schema{
+				Not: &openapi3.SchemaRef{Value: &openapi3.Schema{Required: []string{names[i], names[j]}}},
+			}})
+		}
+	}
+	return nil
+}
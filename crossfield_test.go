@@ -0,0 +1,161 @@
+package apivalidation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cfBooking struct {
+	Method string    `json:"method"`
+	Card   string    `json:"card"`
+	ACH    string    `json:"ach"`
+	Street string    `json:"street"`
+	Zip    string    `json:"zip"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Nights int       `json:"nights"`
+	Guests int       `json:"guests"`
+}
+
+func (b *cfBooking) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&b.Method, v.Required),
+		v.Field(&b.Card, v.RequiredIf(&b.Method, "card")),
+		v.Field(&b.ACH),
+		v.Field(&b.Street),
+		v.Field(&b.Zip, v.RequiredWith(&b.Street)),
+		v.Field(&b.Start),
+		v.Field(&b.End, v.GreaterThanField(&b.Start)),
+		v.Field(&b.Nights),
+		v.Field(&b.Guests, v.GreaterThanField(&b.Nights)),
+		v.MutuallyExclusive(&b.Card, &b.ACH),
+		v.Struct(func(context.Context) error {
+			if b.Nights > 14 {
+				return v.ErrorFor(errors.New("stays over 14 nights need approval"), &b.Nights)
+			}
+			if b.Guests > 10 {
+				return errors.New("too many guests")
+			}
+			return nil
+		}, "Stays over 14 nights need approval."),
+	}
+}
+
+func validBooking() cfBooking {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return cfBooking{Method: "card", Card: "4242", Start: start, End: start.AddDate(0, 0, 2), Nights: 2, Guests: 3}
+}
+
+func cfCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	got := map[string]string{}
+	for _, fe := range v.FieldErrors(err) {
+		got[fe.Pointer] = fe.Code + fe.Message
+	}
+	return got
+}
+
+func TestCrossField_Valid(t *testing.T) {
+	b := validBooking()
+	assert.NoError(t, v.Validate(&b))
+}
+
+func TestCrossField_Errors(t *testing.T) {
+	b := validBooking()
+	b.Card = ""
+	b.Street = "Main St"
+	b.End = b.Start
+	b.Guests = 1
+
+	err := v.Validate(&b)
+	assert.Equal(t, map[string]string{
+		"/card":   "validation_required_ifrequired when method is card",
+		"/zip":    "validation_required_withrequired when street is set",
+		"/end":    "validation_greater_than_fieldmust be greater than start",
+		"/guests": "validation_greater_than_fieldmust be greater than nights",
+	}, cfCodes(t, err))
+}
+
+func TestCrossField_MutuallyExclusive(t *testing.T) {
+	b := validBooking()
+	b.ACH = "123"
+	err := v.Validate(&b)
+	assert.Equal(t, map[string]string{
+		"/card": "validation_mutually_exclusiveonly one of card, ach may be set",
+		"/ach":  "validation_mutually_exclusiveonly one of card, ach may be set",
+	}, cfCodes(t, err))
+}
+
+func TestStruct_Attribution(t *testing.T) {
+	b := validBooking()
+	b.Nights = 20
+	b.Guests = 21
+	err := v.Validate(&b)
+	assert.Equal(t, map[string]string{"/nights": "stays over 14 nights need approval"}, cfCodes(t, err))
+
+	b = validBooking()
+	b.Guests = 11
+	err = v.Validate(&b)
+	fes := v.FieldErrors(err)
+	require.Len(t, fes, 1)
+	assert.Equal(t, "", fes[0].Pointer)
+	assert.Equal(t, "too many guests", fes[0].Message)
+
+	var errs validation.Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "")
+}
+
+func TestStruct_ValidateStruct(t *testing.T) {
+	b := validBooking()
+	b.ACH = "123"
+	b.Card = ""
+	b.Method = "ach"
+	assert.NoError(t, v.ValidateStruct(&b, b.Rules()))
+
+	b.Card = "4242"
+	b.Method = "card"
+	err := v.ValidateStruct(&b, b.Rules())
+	assert.Len(t, cfCodes(t, err), 2)
+}
+
+func TestCrossField_Schema(t *testing.T) {
+	ref, err := v.NewSchemaRefForValue(cfBooking{})
+	require.NoError(t, err)
+	s := ref.Value
+
+	assert.Equal(t, map[string][]string{"street": {"zip"}}, s.Extensions["x-dependentRequired"])
+	require.Len(t, s.AllOf, 1)
+	assert.Equal(t, []string{"card", "ach"}, s.AllOf[0].Value.Not.Value.Required)
+	assert.Equal(t, "Stays over 14 nights need approval.", s.Description)
+	assert.Equal(t, "Required when method is card.", s.Properties["card"].Value.Description)
+	assert.Equal(t, map[string]map[string]any{"card": {"method": "card"}}, s.Extensions["x-requiredIf"])
+	assert.Equal(t, "Must be greater than start.", s.Properties["end"].Value.Description)
+	assert.NotContains(t, s.Required, "card")
+
+	doc := openapi3.T{OpenAPI: "3.0.3", Info: &openapi3.Info{Title: "t", Version: "1"}, Paths: &openapi3.Paths{},
+		Components: &openapi3.Components{Schemas: openapi3.Schemas{"Booking": ref}}}
+	require.NoError(t, doc.Validate(context.Background()))
+
+	ref, err = v.NewSchemaRefForValue(cfBooking{}, v.OpenAPI31())
+	require.NoError(t, err)
+	s = ref.Value
+	assert.NotContains(t, s.Extensions, "x-requiredIf")
+	assert.Empty(t, s.Properties["card"].Value.Description)
+	require.Len(t, s.AllOf, 2)
+	assert.Equal(t, map[string]any{
+		"if": map[string]any{
+			"properties": map[string]any{"method": map[string]any{"const": "card"}},
+			"required":   []string{"method"},
+		},
+		"then": map[string]any{"required": []string{"card"}},
+	}, s.AllOf[1].Value.Extensions)
+}
@@ -1,6 +1,7 @@
 package apivalidation
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -8,50 +9,219 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
-// DateRule validates that a string value matches the given date layout format.
-// Use [Date] to create one, then chain [DateRule.Min] and [DateRule.Max] to
-// constrain the date range for documentation.
+// DateRule validates that a value is a date within a range. Strings must
+// match the rule's layout; [time.Time] values are checked as they are,
+// except that with a layout that has no time of day they are compared by
+// their date in the rule's location. Use
+// [Date] to create one, then chain bounds:
+//
+//	v.Field(&o.Birthday, v.Date("2006-01-02").NotInFuture())
+//	v.Field(&o.End, v.Date(time.RFC3339).After(&o.Start))
+//
+// Bounds are inclusive unless noted. With a layout that has no time of day,
+// relative bounds ([DateRule.NotInFuture], [DateRule.WithinLast]) are
+// truncated to the start of the day in the rule's location, so today's date
+// is not in the future.
 type DateRule struct {
 	validation.DateRule
 	layout   string
 	min, max time.Time
+	// Relative bounds, resolved against the current time on each Validate.
+	notInFuture bool
+	within      time.Duration
+	after       *fieldRef
+	loc         *time.Location
 }
 
 // Date creates a date validation rule with the given layout format.
-// Use .Min() and .Max() to constrain the date range for documentation.
 func Date(layout string) *DateRule {
 	return &DateRule{
 		DateRule: validation.Date(layout),
 		layout:   layout,
+		loc:      time.UTC,
 	}
 }
 
-// Min sets the minimum allowed date for documentation.
+// Validate implements [Rule]. Empty values pass. On failure it returns:
+//   - ozzo's validation_date_invalid error with the "layout" param if a
+//     string does not match the layout
+//   - ozzo's validation_date_out_of_range error with the "min" and/or "max"
+//     and "actual" params, formatted with the layout, if the date is out of
+//     range
+//   - [ErrDateAfterField] if the date is not after the [DateRule.After] field
+//   - [ErrNotString] for values that are neither strings nor times
+func (r *DateRule) Validate(value any) error {
+	value, isNil := validation.Indirect(value)
+	if isNil || validation.IsEmpty(value) {
+		return nil
+	}
+	t, err := r.parse(value)
+	if err != nil {
+		return err
+	}
+	lo, hi := r.bounds(time.Now())
+	day := t
+	if !layoutHasClock(r.layout) {
+		// Compare times by their date, like strings in the layout.
+		day = r.startOfDay(t)
+	}
+	if (!lo.IsZero() && day.Before(lo)) || (!hi.IsZero() && day.After(hi)) {
+		params := map[string]any{"actual": r.format(t)}
+		if !lo.IsZero() {
+			params["min"] = r.format(lo)
+		}
+		if !hi.IsZero() {
+			params["max"] = r.format(hi)
+		}
+		return validation.ErrDateOutOfRange.SetParams(params)
+	}
+	if r.after != nil && !r.after.empty() {
+		other, err := r.parse(r.after.value())
+		if err == nil && !t.After(other) {
+			return ErrDateAfterField.SetParams(map[string]any{"field": r.after.name, "actual": r.format(t), "other": r.format(other)})
+		}
+	}
+	return nil
+}
+
+// parse returns value as a time: strings are parsed with the layout in the
+// rule's location.
+func (r *DateRule) parse(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.ParseInLocation(r.layout, v, r.loc)
+		if err != nil {
+			return time.Time{}, validation.ErrDateInvalid.SetParams(map[string]any{"layout": r.layout})
+		}
+		return t, nil
+	}
+	return time.Time{}, ErrNotString.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+}
+
+// bounds returns the effective range at time now. A zero time is unbounded.
+func (r *DateRule) bounds(now time.Time) (lo, hi time.Time) {
+	lo, hi = r.min, r.max
+	if !layoutHasClock(r.layout) {
+		now = r.startOfDay(now)
+	}
+	if r.notInFuture && (hi.IsZero() || now.Before(hi)) {
+		hi = now
+	}
+	if r.within > 0 {
+		if since := now.Add(-r.within); lo.IsZero() || since.After(lo) {
+			lo = since
+		}
+	}
+	return lo, hi
+}
+
+// startOfDay returns midnight of t's date in the rule's location.
+func (r *DateRule) startOfDay(t time.Time) time.Time {
+	y, m, d := t.In(r.loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, r.loc)
+}
+
+func (r *DateRule) format(t time.Time) string {
+	return t.In(r.loc).Format(r.layout)
+}
+
+// layoutHasClock reports whether layout has a time of day.
+func layoutHasClock(layout string) bool {
+	ref := time.Date(2001, 2, 3, 13, 14, 15, 0, time.UTC)
+	t, err := time.Parse(layout, ref.Format(layout))
+	return err == nil && (t.Hour() != 0 || t.Minute() != 0)
+}
+
+// Min sets the earliest allowed date.
 func (r *DateRule) Min(t time.Time) *DateRule {
 	r.min = t
 	return r
 }
 
-// Max sets the maximum allowed date for documentation.
+// Max sets the latest allowed date.
 func (r *DateRule) Max(t time.Time) *DateRule {
 	r.max = t
 	return r
 }
 
-// Describe implements [Rule] by setting the format and date range on the schema.
+// NotInFuture rejects dates after the current time.
+func (r *DateRule) NotInFuture() *DateRule {
+	r.notInFuture = true
+	return r
+}
+
+// WithinLast rejects dates earlier than d before the current time.
+//
+//	v.Date("2006-01-02").WithinLast(90 * 24 * time.Hour)
+func (r *DateRule) WithinLast(d time.Duration) *DateRule {
+	r.within = d
+	return r
+}
+
+// After rejects dates that are not strictly after the date in the field
+// other points to, a [time.Time] or a string in the rule's layout. The rule
+// passes when the other field is empty or not a valid date.
+func (r *DateRule) After(other any) *DateRule {
+	r.after = &fieldRef{ptr: other}
+	return r
+}
+
+// In sets the location strings without a UTC offset are parsed in, and in
+// which relative bounds of a layout without a time of day start the day.
+// The default is UTC.
+func (r *DateRule) In(loc *time.Location) *DateRule {
+	r.loc = loc
+	return r
+}
+
+func (r *DateRule) bindFields(name func(fieldPtr any) string) {
+	if r.after != nil {
+		r.after.bind(name)
+	}
+}
+
+// Describe implements [Rule] by setting the format and describing the date
+// range. Layouts matching RFC 3339 are documented as format date or
+// date-time; other layouts are written to format as is. Fields that already
+// have a format, such as [time.Time] fields (date-time), keep it.
 func (r *DateRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
-	ref.Value.Format = r.layout
+	if ref.Value.Format == "" {
+		ref.Value.Format = dateFormat(r.layout)
+	}
+	var parts []string
 	if !r.min.IsZero() {
-		if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
-			ref.Value.Description += " "
-		}
-		ref.Value.Description += "> " + r.min.String()
+		parts = append(parts, Messages.Describe("describe_date_min", ">= {{.min}}", map[string]any{"min": r.format(r.min)}))
 	}
 	if !r.max.IsZero() {
+		parts = append(parts, Messages.Describe("describe_date_max", "<= {{.max}}", map[string]any{"max": r.format(r.max)}))
+	}
+	if r.notInFuture {
+		parts = append(parts, Messages.Describe("describe_date_not_in_future", "Not in the future.", nil))
+	}
+	if r.within > 0 {
+		parts = append(parts, Messages.Describe("describe_date_within_last", "Within the last {{.duration}}.", map[string]any{"duration": r.within.String()}))
+	}
+	if r.after != nil && r.after.name != "" {
+		parts = append(parts, Messages.Describe("describe_date_after", "After {{.field}}.", map[string]any{"field": r.after.name}))
+	}
+	for _, part := range parts {
 		if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
 			ref.Value.Description += " "
 		}
-		ref.Value.Description += "< " + r.max.String()
+		ref.Value.Description += part
 	}
 	return nil
 }
+
+// dateFormat returns the schema format for layout.
+func dateFormat(layout string) string {
+	switch layout {
+	case time.DateOnly:
+		return "date"
+	case time.RFC3339, time.RFC3339Nano:
+		return "date-time"
+	}
+	return layout
+}
@@ -0,0 +1,104 @@
+package apivalidation_test
+
+import (
+	"testing"
+	"time"
+
+	v "github.com/Gobd/apivalidation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate_Range(t *testing.T) {
+	rule := v.Date("2006-01-02").
+		Min(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)).
+		Max(time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, rule.Validate("2020-01-01"))
+	assert.NoError(t, rule.Validate("2030-12-31"))
+	assert.NoError(t, rule.Validate(""))
+
+	err := rule.Validate("2019-12-31")
+	require.Error(t, err)
+	verr := err.(validation.Error)
+	assert.Equal(t, validation.ErrDateOutOfRange.Code(), verr.Code())
+	assert.Equal(t, map[string]any{"min": "2020-01-01", "max": "2030-12-31", "actual": "2019-12-31"}, verr.Params())
+
+	assert.Error(t, rule.Validate(time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, validation.ErrDateInvalid.Code(), rule.Validate("01/02/2020").(validation.Error).Code())
+	assert.Equal(t, v.ErrNotString.Code(), rule.Validate(42).(validation.Error).Code())
+}
+
+func TestDate_Relative(t *testing.T) {
+	now := time.Now()
+
+	assert.NoError(t, v.Date(time.RFC3339).NotInFuture().Validate(now.Add(-time.Minute)))
+	assert.Error(t, v.Date(time.RFC3339).NotInFuture().Validate(now.Add(time.Hour)))
+	assert.NoError(t, v.Date(time.DateOnly).NotInFuture().Validate(now.UTC().Format(time.DateOnly)), "today is not in the future")
+	assert.Error(t, v.Date(time.DateOnly).NotInFuture().Validate(now.UTC().AddDate(0, 0, 1).Format(time.DateOnly)))
+
+	// time.Time values are compared by their date with date-only layouts.
+	assert.NoError(t, v.Date(time.DateOnly).NotInFuture().Validate(now.Add(-time.Minute)))
+	assert.Error(t, v.Date(time.DateOnly).NotInFuture().Validate(now.AddDate(0, 0, 1)))
+
+	within := v.Date(time.DateOnly).WithinLast(90 * 24 * time.Hour)
+	assert.NoError(t, within.Validate(now.UTC().AddDate(0, 0, -90).Format(time.DateOnly)))
+	assert.Error(t, within.Validate(now.UTC().AddDate(0, 0, -91).Format(time.DateOnly)))
+}
+
+func TestDate_In(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	rule := v.Date("2006-01-02 15:04").In(tokyo).Max(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, rule.Validate("2024-01-01 09:00"), "09:00 in Tokyo is midnight UTC")
+	err := rule.Validate("2024-01-01 09:01")
+	require.Error(t, err)
+	assert.Equal(t, "2024-01-01 09:00", err.(validation.Error).Params()["max"])
+}
+
+type dateStay struct {
+	Start time.Time `json:"start"`
+	End   string    `json:"end"`
+	Born  time.Time `json:"born"`
+	Due   string    `json:"due"`
+}
+
+func (s *dateStay) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&s.Start, v.Required, v.Date(time.RFC3339).NotInFuture()),
+		v.Field(&s.End, v.Date(time.RFC3339).After(&s.Start)),
+		v.Field(&s.Born, v.Date(time.DateOnly)),
+		v.Field(&s.Due, v.Date(time.DateOnly)),
+	}
+}
+
+func TestDate_After(t *testing.T) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.NoError(t, v.Validate(&dateStay{Start: start, End: start.Add(time.Hour).Format(time.RFC3339)}))
+	assert.NoError(t, v.Validate(&dateStay{Start: start}))
+
+	errs := v.FieldErrors(v.Validate(&dateStay{Start: start, End: start.Format(time.RFC3339)}))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/end", errs[0].Pointer)
+	assert.Equal(t, v.ErrDateAfterField.Code(), errs[0].Code)
+	assert.Equal(t, "start", errs[0].Params["field"])
+
+	errs = v.FieldErrors(v.Validate(&dateStay{Start: time.Now().Add(time.Hour)}))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/start", errs[0].Pointer)
+}
+
+func TestDate_Schema(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(dateStay{})
+	require.NoError(t, err)
+
+	start := schema.Value.Properties["start"].Value
+	assert.Equal(t, "date-time", start.Format)
+	assert.Equal(t, "Not in the future.", start.Description)
+	end := schema.Value.Properties["end"].Value
+	assert.Equal(t, "date-time", end.Format)
+	assert.Equal(t, "After start.", end.Description)
+	assert.Equal(t, "date-time", schema.Value.Properties["born"].Value.Format, "time.Time marshals as date-time")
+	assert.Equal(t, "date", schema.Value.Properties["due"].Value.Format)
+}
@@ -0,0 +1,151 @@
+package apivalidation
+
+import (
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/Gobd/apivalidation/internal/currency"
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// decimalPattern is the pattern documented for decimal strings, and the
+// form [DecimalRange] and [Money] accept.
+const decimalPattern = `^-?\d+(\.\d+)?$`
+
+var decimalRegexp = regexp.MustCompile(decimalPattern)
+
+// DecimalRange returns a validation rule that checks if a decimal is between
+// min and max, inclusive, comparing exactly. An empty bound is not checked.
+// Strings must be plain decimals such as "-12.50"; [encoding/json.Number]
+// values and numbers are also accepted. String fields are documented as
+// type string, format decimal with a pattern, and numeric fields with
+// minimum and maximum.
+//
+// On failure it returns [ErrNotDecimal], or ozzo's min and max errors with
+// the "threshold", "min" or "max", and "actual" params. DecimalRange panics
+// if a bound is not a decimal.
+//
+//	v.Field(&p.Amount, v.DecimalRange("0.01", "10000.00"))
+func DecimalRange(min, max string) Rule {
+	r := &decimalRangeRule{}
+	for _, bound := range []string{min, max} {
+		if bound != "" && !decimalRegexp.MatchString(bound) {
+			panic("apivalidation: DecimalRange bound " + bound + " is not a decimal")
+		}
+	}
+	if min != "" {
+		r.bounds = append(r.bounds, Min(min))
+	}
+	if max != "" {
+		r.bounds = append(r.bounds, Max(max))
+	}
+	return r
+}
+
+type decimalRangeRule struct {
+	bounds []Rule
+}
+
+func (r *decimalRangeRule) Validate(value any) error {
+	value, isNil := validation.Indirect(value)
+	if isNil || validation.IsEmpty(value) {
+		return nil
+	}
+	if _, err := parseDecimal(value); err != nil {
+		return err
+	}
+	for _, bound := range r.bounds {
+		if err := bound.Validate(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *decimalRangeRule) Describe(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	describeDecimal(ref.Value)
+	for _, bound := range r.bounds {
+		if err := bound.Describe(name, schema, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Money returns a validation rule that checks if an amount has no more
+// decimal places than the minor unit of the ISO 4217 currency in the field
+// currency points to: 2 for "USD", 0 for "JPY", 3 for "KWD". Amounts are
+// accepted in the same forms as [DecimalRange]. Currencies that are empty,
+// not ISO 4217 (see is.CurrencyCode), or have no minor unit (such as "XAU")
+// only require a decimal; validate the currency field itself to reject them.
+//
+// On failure it returns [ErrDecimalMax] with the "max", "currency" and
+// "actual" params, or [ErrNotDecimal].
+//
+//	v.Field(&p.Amount, v.Required, v.Money(&p.Currency)),
+//	v.Field(&p.Currency, v.Required, is.CurrencyCode),
+func Money[T ~string](currency *T) Rule {
+	return &moneyRule{currency: fieldRef{ptr: currency}}
+}
+
+type moneyRule struct {
+	currency fieldRef
+}
+
+func (r *moneyRule) bindFields(name func(fieldPtr any) string) { r.currency.bind(name) }
+
+func (r *moneyRule) Validate(value any) error {
+	value, isNil := validation.Indirect(value)
+	if isNil || validation.IsEmpty(value) {
+		return nil
+	}
+	n, err := parseDecimal(value)
+	if err != nil {
+		return err
+	}
+	code := reflect.ValueOf(r.currency.ptr).Elem().String()
+	units, ok := currency.MinorUnits(code)
+	if !ok {
+		return nil
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil)
+	if new(big.Rat).Mul(n, new(big.Rat).SetInt(scale)).IsInt() {
+		return nil
+	}
+	return ErrDecimalMax.SetParams(map[string]any{"max": units, "currency": code, "actual": value})
+}
+
+func (r *moneyRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	describeDecimal(ref.Value)
+	setExtension(ref.Value, "x-currency-field", r.currency.name)
+	if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
+		ref.Value.Description += " "
+	}
+	ref.Value.Description += Messages.Describe("describe_money", "Amount in the currency of {{.field}}, with at most its minor unit decimals.",
+		map[string]any{"field": r.currency.name})
+	return nil
+}
+
+// describeDecimal documents a string schema as a decimal. Numeric schemas
+// are left as they are.
+func describeDecimal(s *openapi3.Schema) {
+	if s.Type != nil && !s.Type.Is(openapi3.TypeString) {
+		return
+	}
+	s.Type = &openapi3.Types{openapi3.TypeString}
+	s.Format = "decimal"
+	s.Pattern = decimalPattern
+}
+
+// parseDecimal converts value to an exact rational like [toRat], but only
+// accepts strings matching decimalPattern.
+func parseDecimal(value any) (*big.Rat, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.String && !decimalRegexp.MatchString(rv.String()) {
+		return nil, ErrNotDecimal.SetParams(map[string]any{"actual": rv.String()})
+	}
+	return toRat(value)
+}
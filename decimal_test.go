@@ -0,0 +1,130 @@
+package apivalidation_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/Gobd/apivalidation/apivalidationtest"
+	"github.com/Gobd/apivalidation/is"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decimalPayment struct {
+	Amount   string      `json:"amount"`
+	Fee      json.Number `json:"fee"`
+	Currency string      `json:"currency"`
+}
+
+func (p *decimalPayment) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.Amount, v.Required, v.Money(&p.Currency)),
+		v.Field(&p.Fee, v.DecimalRange("0", "99.99")),
+		v.Field(&p.Currency, v.Required, is.CurrencyCode),
+	}
+}
+
+func TestMinMax_Decimal(t *testing.T) {
+	err := v.Max(0.1).Validate("0.1000000000000000001")
+	require.Error(t, err)
+	verr := err.(validation.Error)
+	assert.Equal(t, validation.ErrMaxLessEqualThanRequired.Code(), verr.Code())
+	assert.Equal(t, "0.1000000000000000001", verr.Params()["actual"])
+	assert.NoError(t, v.Max(0.1).Validate(json.Number("0.1")))
+	assert.Equal(t, v.ErrNotFloat.Code(), v.Max(0.1).Validate("abc").(validation.Error).Code())
+
+	// Forms strconv.ParseFloat accepts keep working.
+	assert.NoError(t, v.Min(0.5).Validate("+1"))
+	assert.NoError(t, v.Min(0.5).Validate(".7"))
+	assert.NoError(t, v.Min(0.5).Validate("1."))
+	assert.NoError(t, v.Min(0.5).Validate("0x1p0"))
+	assert.Error(t, v.Min(0.5).Validate(".3"))
+	assert.Equal(t, validation.ErrMaxLessEqualThanRequired.Code(), v.Max(0.1).Validate("Inf").(validation.Error).Code())
+
+	assert.NoError(t, v.Min("0.01").Validate("0.01"))
+	assert.NoError(t, v.Min("0.01").Validate(0.5))
+	err = v.Min("0.01").Validate(json.Number("0.009"))
+	require.Error(t, err)
+	assert.Equal(t, map[string]any{"threshold": "0.01", "min": "0.01", "actual": json.Number("0.009")}, err.(validation.Error).Params())
+	assert.Equal(t, validation.ErrMaxLessThanRequired.Code(), v.ExclusiveMax("10").Validate("10.00").(validation.Error).Code())
+}
+
+func TestDecimalRange(t *testing.T) {
+	rule := v.DecimalRange("0.01", "100")
+
+	assert.NoError(t, rule.Validate("0.01"))
+	assert.NoError(t, rule.Validate(json.Number("100.00")))
+	assert.NoError(t, rule.Validate(""))
+	assert.Equal(t, validation.ErrMinGreaterEqualThanRequired.Code(), rule.Validate("0.001").(validation.Error).Code())
+	assert.Equal(t, validation.ErrMaxLessEqualThanRequired.Code(), rule.Validate(100.5).(validation.Error).Code())
+	assert.Equal(t, v.ErrNotDecimal.Code(), rule.Validate("1e2").(validation.Error).Code())
+	assert.NoError(t, v.DecimalRange("", "1").Validate("-5"))
+
+	assert.Panics(t, func() { v.DecimalRange("abc", "") })
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		code             string
+	}{
+		{"10.25", "USD", ""},
+		{"10.255", "USD", v.ErrDecimalMax.Code()},
+		{"10.250", "USD", ""},
+		{"1000", "JPY", ""},
+		{"1000.5", "JPY", v.ErrDecimalMax.Code()},
+		{"1.125", "KWD", ""},
+		{"1.12345", "XAU", ""},
+		{"1.12345", "", ""},
+		{"12,50", "EUR", v.ErrNotDecimal.Code()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			err := v.Validate(&decimalPayment{Amount: tt.amount, Currency: tt.currency})
+			fes := v.FieldErrors(err)
+			var codes []string
+			for _, fe := range fes {
+				if fe.Pointer == "/amount" {
+					codes = append(codes, fe.Code)
+				}
+			}
+			if tt.code == "" {
+				assert.Empty(t, codes)
+			} else {
+				assert.Equal(t, []string{tt.code}, codes)
+			}
+		})
+	}
+
+	err := v.Money(new(string)).Validate("1")
+	assert.NoError(t, err)
+	currency := "USD"
+	err = v.Money(&currency).Validate(json.Number("0.001"))
+	require.Error(t, err)
+	assert.Equal(t, map[string]any{"max": 2, "currency": "USD", "actual": json.Number("0.001")}, err.(validation.Error).Params())
+}
+
+func TestDecimal_Schema(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(decimalPayment{})
+	require.NoError(t, err)
+
+	amount := schema.Value.Properties["amount"].Value
+	assert.Equal(t, []string{"string"}, amount.Type.Slice())
+	assert.Equal(t, "decimal", amount.Format)
+	assert.Equal(t, `^-?\d+(\.\d+)?$`, amount.Pattern)
+	assert.Equal(t, "currency", amount.Extensions["x-currency-field"])
+	assert.Contains(t, amount.Description, "currency of currency")
+
+	// json.Number marshals as a number, so the bounds are numeric.
+	fee := schema.Value.Properties["fee"].Value
+	assert.Equal(t, []string{"number"}, fee.Type.Slice())
+	assert.Empty(t, fee.Format)
+	assert.Equal(t, 0.0, *fee.Min)
+	assert.Equal(t, 99.99, *fee.Max)
+}
+
+func TestDecimal_Parity(t *testing.T) {
+	apivalidationtest.CheckParity(t, decimalPayment{Amount: "1.00", Fee: "1", Currency: "USD"})
+}
@@ -0,0 +1,212 @@
+package apivalidation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	rawMessageType      = reflect.TypeFor[json.RawMessage]()
+)
+
+// DecodeAndValidateWith is like [DecodeAndValidateContext] but configurable
+// with [Strict], [MaxBytes] and [UseNumber]. Unlike DecodeAndValidate it
+// rejects data after the first JSON value with [ErrTrailingData].
+//
+//	err := v.DecodeAndValidateWith(r.Context(), r.Body, &order, v.Strict(), v.MaxBytes(1<<20))
+func DecodeAndValidateWith(ctx context.Context, r io.Reader, dst any, opts ...Option) error {
+	o := newOptions(opts)
+	if o.maxBytes > 0 {
+		r = io.LimitReader(r, o.maxBytes+1)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if o.maxBytes > 0 && int64(len(b)) > o.maxBytes {
+		return ErrBodyTooLarge.SetParams(map[string]any{"max": o.maxBytes})
+	}
+	return decodeAndValidate(ctx, b, dst, o)
+}
+
+// UnmarshalAndValidateWith is like [UnmarshalAndValidateCtx] but configurable
+// with [Strict], [MaxBytes] and [UseNumber].
+func UnmarshalAndValidateWith(ctx context.Context, b []byte, dst any, opts ...Option) error {
+	o := newOptions(opts)
+	if o.maxBytes > 0 && int64(len(b)) > o.maxBytes {
+		return ErrBodyTooLarge.SetParams(map[string]any{"max": o.maxBytes})
+	}
+	return decodeAndValidate(ctx, b, dst, o)
+}
+
+func decodeAndValidate(ctx context.Context, b []byte, dst any, o options) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	if o.useNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(dst); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingData.SetParams(map[string]any{"offset": decoder.InputOffset()})
+	}
+	return validateDecoded(ctx, b, dst, o)
+}
+
+// validateDecoded normalizes and validates dst after it was decoded from b,
+// adding unknown fields in b to the errors in strict mode.
+func validateDecoded(ctx context.Context, b []byte, dst any, o options) error {
+	var unknown validation.Errors
+	if o.strict {
+		if u := unknownFields(b, reflect.TypeOf(dst)); u != nil {
+			unknown, _ = translate(ctx, u).(validation.Errors)
+		}
+	}
+
+	normalizeRecursive(ctx, dst)
+	err := ValidateCtx(ctx, dst)
+	if len(unknown) == 0 {
+		return err
+	}
+	if err == nil {
+		return unknown
+	}
+	if errs, ok := err.(validation.Errors); ok {
+		return mergeErrors(errs, unknown)
+	}
+	return err
+}
+
+// unknownFields walks raw against t and reports object keys that
+// encoding/json would silently drop. Keys are matched case-insensitively,
+// like encoding/json does. Values that do not fit t are left for the decoder
+// to report.
+func unknownFields(raw []byte, t reflect.Type) validation.Errors {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if u := unionOf(t); u != nil {
+		tag, _ := u.discriminator(raw, t)
+		vt, ok := u.variants[tag]
+		if !ok {
+			return nil
+		}
+		errs := unknownFields(raw, vt)
+		delete(errs, u.property)
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs
+	}
+	if t == rawMessageType || reflect.PointerTo(t).Implements(jsonUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+
+	errs := validation.Errors{}
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		fields := jsonFieldTypes(t)
+		for k, v := range obj {
+			ft, ok := fields[strings.ToLower(k)]
+			if !ok {
+				errs[k] = ErrUnknownField
+				continue
+			}
+			if sub := unknownFields(v, ft); len(sub) > 0 {
+				errs[k] = sub
+			}
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		for k, v := range obj {
+			if sub := unknownFields(v, t.Elem()); len(sub) > 0 {
+				errs[k] = sub
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var arr []json.RawMessage
+		if json.Unmarshal(raw, &arr) != nil {
+			return nil
+		}
+		for i, v := range arr {
+			if sub := unknownFields(v, t.Elem()); len(sub) > 0 {
+				errs[strconv.Itoa(i)] = sub
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// jsonFieldTypes maps the lower-cased JSON names of t's fields to their
+// types, including fields promoted from untagged embedded structs.
+func jsonFieldTypes(t reflect.Type) map[string]reflect.Type {
+	out := map[string]reflect.Type{}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" {
+			et := sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				for k, v := range jsonFieldTypes(et) {
+					if _, ok := out[k]; !ok {
+						out[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		out[strings.ToLower(name)] = sf.Type
+	}
+	return out
+}
+
+// mergeErrors merges b into a, recursing where both have nested errors
+// under the same key.
+func mergeErrors(a, b validation.Errors) validation.Errors {
+	for k, be := range b {
+		ae, ok := a[k]
+		if !ok || ae == nil {
+			a[k] = be
+			continue
+		}
+		aErrs, aOK := ae.(validation.Errors)
+		bErrs, bOK := be.(validation.Errors)
+		if aOK && bOK {
+			a[k] = mergeErrors(aErrs, bErrs)
+		}
+	}
+	return a
+}
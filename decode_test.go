@@ -0,0 +1,92 @@
+package apivalidation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeAny struct {
+	Value any `json:"value"`
+}
+
+func TestDecodeAndValidateWith_Strict(t *testing.T) {
+	body := `{"items":[{"sku":"a","colour":"red"},{}],"by_key":{"k":{"sku":"b","qty":1}},"ID":"x","extra":true}`
+	var o feOrder
+	err := v.DecodeAndValidateWith(context.Background(), strings.NewReader(body), &o, v.Strict())
+	require.Error(t, err)
+
+	got := map[string]string{}
+	for _, fe := range v.FieldErrors(err) {
+		got[fe.Pointer] = fe.Code
+	}
+	assert.Equal(t, map[string]string{
+		"/extra":          "decode_unknown_field",
+		"/items/0/colour": "decode_unknown_field",
+		"/items/1/sku":    "validation_required",
+		"/by_key/k/qty":   "decode_unknown_field",
+	}, got)
+}
+
+func TestDecodeAndValidateWith_NotStrict(t *testing.T) {
+	var o feOrder
+	err := v.DecodeAndValidateWith(context.Background(), strings.NewReader(`{"ID":"x","extra":true}`), &o)
+	assert.NoError(t, err)
+}
+
+func TestDecodeAndValidateWith_EmbeddedAndCaseInsensitive(t *testing.T) {
+	var o feOrder
+	err := v.DecodeAndValidateWith(context.Background(), strings.NewReader(`{"id":"x","ITEMS":[]}`), &o, v.Strict())
+	assert.NoError(t, err)
+}
+
+func TestDecodeAndValidateWith_TrailingData(t *testing.T) {
+	var o feOrder
+	err := v.DecodeAndValidateWith(context.Background(), strings.NewReader(`{"ID":"x"}{"ID":"y"}`), &o)
+	require.Error(t, err)
+	p := v.NewProblem(err)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "decode_trailing_data", p.Errors[0].Code)
+
+	assert.NoError(t, v.DecodeAndValidateWith(context.Background(), strings.NewReader("{\"ID\":\"x\"}\n"), &o))
+}
+
+func TestDecodeAndValidateWith_MaxBytes(t *testing.T) {
+	var o feOrder
+	body := `{"ID":"x"}`
+	assert.NoError(t, v.DecodeAndValidateWith(context.Background(), strings.NewReader(body), &o, v.MaxBytes(int64(len(body)))))
+
+	err := v.DecodeAndValidateWith(context.Background(), strings.NewReader(body), &o, v.MaxBytes(4))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, v.NewProblem(err).Status)
+
+	err = v.UnmarshalAndValidateWith(context.Background(), []byte(body), &o, v.MaxBytes(4))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, v.NewProblem(err).Status)
+}
+
+func TestUnmarshalAndValidateWith_UseNumber(t *testing.T) {
+	var d decodeAny
+	require.NoError(t, v.UnmarshalAndValidateWith(context.Background(), []byte(`{"value":12345678901234567890}`), &d, v.UseNumber()))
+	assert.Equal(t, json.Number("12345678901234567890"), d.Value)
+}
+
+func TestNewSchemaRefForValue_Strict(t *testing.T) {
+	ref, err := v.NewSchemaRefForValue(feOrder{}, v.Strict())
+	require.NoError(t, err)
+	require.NotNil(t, ref.Value.AdditionalProperties.Has)
+	assert.False(t, *ref.Value.AdditionalProperties.Has)
+	item := ref.Value.Properties["items"].Value.Items.Value
+	require.NotNil(t, item.AdditionalProperties.Has)
+	assert.False(t, *item.AdditionalProperties.Has)
+
+	ref, err = v.NewSchemaRefForValue(feOrder{})
+	require.NoError(t, err)
+	assert.Nil(t, ref.Value.AdditionalProperties.Has)
+}
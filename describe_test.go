@@ -71,15 +71,19 @@ func TestDescribe_Max(t *testing.T) {
 }
 
 func TestDescribe_MinMax_StringType(t *testing.T) {
-	// When the ref is a string type, DescNew sets the Format field
+	// Strings compared with a float threshold are documented as decimals.
 	schema, ref := newTestStringSchemaRef()
 
 	err := Min(0.0).Describe("amount", schema, ref)
 	require.NoError(t, err)
 
-	assert.NotEmpty(t, ref.Value.Format)
+	assert.Equal(t, "decimal", ref.Value.Format)
 	require.NotNil(t, ref.Value.Min)
 	assert.Equal(t, float64(0), *ref.Value.Min)
+
+	schema, ref = newTestStringSchemaRef()
+	require.NoError(t, Max(10).Describe("count", schema, ref))
+	assert.Empty(t, ref.Value.Format)
 }
 
 func TestDescribe_Length(t *testing.T) {
@@ -88,10 +92,48 @@ func TestDescribe_Length(t *testing.T) {
 	err := Length(3, 255).Describe("title", schema, ref)
 	require.NoError(t, err)
 
-	require.NotNil(t, ref.Value.Min)
-	require.NotNil(t, ref.Value.Max)
-	assert.Equal(t, float64(3), *ref.Value.Min)
-	assert.Equal(t, float64(255), *ref.Value.Max)
+	assert.Nil(t, ref.Value.Min)
+	assert.Nil(t, ref.Value.Max)
+	assert.Equal(t, uint64(3), ref.Value.MinLength)
+	require.NotNil(t, ref.Value.MaxLength)
+	assert.Equal(t, uint64(255), *ref.Value.MaxLength)
+}
+
+func TestDescribe_Length_Collections(t *testing.T) {
+	schema, ref := newTestSchemaRef()
+	ref.Value.Type = &openapi3.Types{openapi3.TypeArray}
+	require.NoError(t, Length(1, 0).Describe("tags", schema, ref))
+	assert.Equal(t, uint64(1), ref.Value.MinItems)
+	assert.Nil(t, ref.Value.MaxItems, "a maximum of 0 is unbounded")
+	assert.Zero(t, ref.Value.MinLength)
+
+	schema, ref = newTestSchemaRef()
+	ref.Value.Type = &openapi3.Types{openapi3.TypeObject}
+	require.NoError(t, Length(0, 5).Describe("labels", schema, ref))
+	require.NotNil(t, ref.Value.MaxProps)
+	assert.Equal(t, uint64(5), *ref.Value.MaxProps)
+}
+
+func TestDescribe_Size(t *testing.T) {
+	schema, ref := newTestSchemaRef()
+	require.NoError(t, MinItems(1).Describe("tags", schema, ref))
+	require.NoError(t, MaxItems(3).Describe("tags", schema, ref))
+	require.NoError(t, MinProperties(2).Describe("tags", schema, ref))
+	require.NoError(t, MaxProperties(4).Describe("tags", schema, ref))
+
+	assert.Equal(t, uint64(1), ref.Value.MinItems)
+	assert.Equal(t, uint64(3), *ref.Value.MaxItems)
+	assert.Equal(t, uint64(2), ref.Value.MinProps)
+	assert.Equal(t, uint64(4), *ref.Value.MaxProps)
+}
+
+func TestDescribe_When_Sizes(t *testing.T) {
+	schema, ref := newTestSchemaRef()
+
+	err := When(true, "is card", Length(12, 19)).Else(MaxItems(2)).Describe("card", schema, ref)
+	require.NoError(t, err)
+
+	assert.Equal(t, "when is card: min length 12, max length 19 else: max items 2", ref.Value.Description)
 }
 
 func TestDescribe_In(t *testing.T) {
@@ -105,23 +147,43 @@ func TestDescribe_In(t *testing.T) {
 
 func TestDescribe_Each_SingleRule(t *testing.T) {
 	schema, ref := newTestSchemaRef()
+	ref.Value = openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
 
-	// Each with a single rule should apply that rule
-	err := Each(In("x", "y")).Describe("tags", schema, ref)
+	// Each documents its rules on the items, not the array
+	err := Each(In("x", "y"), Length(1, 10)).Describe("tags", schema, ref)
 	require.NoError(t, err)
 
-	assert.Equal(t, []any{"x", "y"}, ref.Value.Enum)
+	assert.Empty(t, ref.Value.Enum)
+	assert.Zero(t, ref.Value.MinItems)
+	assert.Equal(t, []any{"x", "y"}, ref.Value.Items.Value.Enum)
+	assert.Equal(t, uint64(1), ref.Value.Items.Value.MinLength)
 }
 
 func TestDescribe_Each_MultipleRules(t *testing.T) {
 	schema, ref := newTestSchemaRef()
+	ref.Value = openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
 
 	err := Each(Required, In("x", "y")).Describe("tags", schema, ref)
 	require.NoError(t, err)
 
-	// All rules should be applied
-	assert.Contains(t, schema.Required, "tags")
-	assert.Equal(t, []any{"x", "y"}, ref.Value.Enum)
+	// Required applies to elements, so the field itself is not required
+	assert.Empty(t, schema.Required)
+	assert.Equal(t, []any{"x", "y"}, ref.Value.Items.Value.Enum)
+}
+
+func TestDescribe_EachKeyValue(t *testing.T) {
+	schema, ref := newTestSchemaRef()
+	ref.Value = openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewIntegerSchema())
+
+	require.NoError(t, EachKey(Length(1, 8)).Describe("labels", schema, ref))
+	require.NoError(t, EachValue(Min(1)).Describe("labels", schema, ref))
+
+	names := ref.Value.Extensions["x-propertyNames"].(*openapi3.Schema)
+	require.NotNil(t, names.MaxLength)
+	assert.Equal(t, uint64(8), *names.MaxLength)
+	assert.Nil(t, names.Type)
+	assert.Equal(t, float64(1), *ref.Value.AdditionalProperties.Schema.Value.Min)
+	assert.True(t, ref.Value.AdditionalProperties.Schema.Value.Type.Is(openapi3.TypeInteger))
 }
 
 func TestDescribe_Unique(t *testing.T) {
@@ -139,7 +201,7 @@ func TestDescribe_Date_Basic(t *testing.T) {
 	err := Date("2006-01-02").Describe("dob", schema, ref)
 	require.NoError(t, err)
 
-	assert.Equal(t, "2006-01-02", ref.Value.Format)
+	assert.Equal(t, "date", ref.Value.Format)
 }
 
 func TestDescribe_Date_WithMinMax(t *testing.T) {
@@ -151,9 +213,8 @@ func TestDescribe_Date_WithMinMax(t *testing.T) {
 	err := Date("2006-01-02").Min(minTime).Max(maxTime).Describe("eventDate", schema, ref)
 	require.NoError(t, err)
 
-	assert.Equal(t, "2006-01-02", ref.Value.Format)
-	assert.Contains(t, ref.Value.Description, "> "+minTime.String())
-	assert.Contains(t, ref.Value.Description, "< "+maxTime.String())
+	assert.Equal(t, "date", ref.Value.Format)
+	assert.Equal(t, ">= 2020-01-01 <= 2030-12-31", ref.Value.Description)
 }
 
 func TestDescribe_When_WithRules(t *testing.T) {
@@ -350,8 +411,12 @@ func TestDescribe_KeyIn(t *testing.T) {
 
 	err := KeyIn("a", "b", "c").Describe("config", schema, ref)
 	require.NoError(t, err)
+	require.NoError(t, KeyPattern(`^[a-z]$`).Describe("config", schema, ref))
 
-	assert.Contains(t, ref.Value.Description, "keys must be in (a,b,c)")
+	assert.Empty(t, ref.Value.Description)
+	names := ref.Value.Extensions["x-propertyNames"].(*openapi3.Schema)
+	assert.Equal(t, []any{"a", "b", "c"}, names.Enum)
+	assert.Equal(t, `^[a-z]$`, names.Pattern)
 }
 
 func TestDescribe_HasAlphabetic(t *testing.T) {
@@ -392,3 +457,12 @@ func TestDescribe_StringRule_Custom(t *testing.T) {
 
 	assert.Equal(t, "must not be blank", ref.Value.Description)
 }
+
+func TestDescribe_Date_CustomLayout(t *testing.T) {
+	schema, ref := newTestSchemaRef()
+
+	err := Date("01/02/2006").Describe("dob", schema, ref)
+	require.NoError(t, err)
+
+	assert.Equal(t, "01/02/2006", ref.Value.Format)
+}
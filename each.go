@@ -1,28 +1,132 @@
 package apivalidation
 
 import (
+	"fmt"
+	"reflect"
+	"strconv"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
-// Each returns a validation rule that applies the given rules to each element of a slice or array.
+// eachTarget is the part of a collection an each rule applies to.
+type eachTarget int
+
+const (
+	eachElement eachTarget = iota // slice and array elements, or map values
+	eachKey
+	eachValue
+)
+
+// Each returns a validation rule that applies the given rules to each element of a slice or array,
+// or each value of a map. The rules are documented on the schema's items (additionalProperties for maps).
 func Each(rules ...Rule) Rule {
-	return &eachRule{
-		validation.Each(convertRules(rules...)...),
-		rules,
-	}
+	return &eachRule{target: eachElement, rules: rules}
+}
+
+// EachKey returns a validation rule that applies the given rules to each key of a map.
+// The rules are documented as propertyNames (x-propertyNames in OpenAPI 3.0).
+//
+//	v.Field(&o.Labels, v.EachKey(v.Pattern(`^[a-z][a-z0-9_]*$`)), v.EachValue(v.Length(1, 64)))
+func EachKey(rules ...Rule) Rule {
+	return &eachRule{target: eachKey, rules: rules}
+}
+
+// EachValue returns a validation rule that applies the given rules to each value of a map.
+// The rules are documented on the schema's additionalProperties.
+func EachValue(rules ...Rule) Rule {
+	return &eachRule{target: eachValue, rules: rules}
 }
 
 type eachRule struct {
-	validation.EachRule
-	rules []Rule
+	target eachTarget
+	rules  []Rule
 }
 
-func (r *eachRule) Describe(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
-	for i := range r.rules {
-		if err := r.rules[i].Describe(name, schema, ref); err != nil {
+// Validate implements [Rule]. Element errors are keyed by index (or map key);
+// non-iterable values return [ErrNotIterable], and non-map values passed to
+// [EachKey] or [EachValue] return [ErrNotMap].
+func (r *eachRule) Validate(value any) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	rules := convertRules(r.rules...)
+	errs := validation.Errors{}
+	switch {
+	case rv.Kind() == reflect.Map:
+		for _, key := range rv.MapKeys() {
+			elem := rv.MapIndex(key)
+			if r.target == eachKey {
+				elem = key
+			}
+			if err := validation.Validate(elem.Interface(), rules...); err != nil {
+				errs[fmt.Sprintf("%v", key.Interface())] = err
+			}
+		}
+	case r.target != eachElement:
+		return ErrNotMap.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		for i := range rv.Len() {
+			if err := validation.Validate(rv.Index(i).Interface(), rules...); err != nil {
+				errs[strconv.Itoa(i)] = err
+			}
+		}
+	default:
+		return ErrNotIterable
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (r *eachRule) bindFields(name func(fieldPtr any) string) {
+	for _, rule := range r.rules {
+		bindRule(rule, name)
+	}
+}
+
+// Describe documents the rules on the element schema: items for arrays,
+// additionalProperties for maps, or x-propertyNames for [EachKey]. Rules
+// that document the parent, such as [Required], have no effect.
+func (r *eachRule) Describe(name string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	return r.describe(name, ref, options{})
+}
+
+func (r *eachRule) describe31(name string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	return r.describe(name, ref, options{openapi31: true})
+}
+
+// describe is Describe, describing the element rules with o.
+func (r *eachRule) describe(name string, ref *openapi3.SchemaRef, o options) error {
+	var elem *openapi3.SchemaRef
+	switch {
+	case r.target == eachKey:
+		elem = &openapi3.SchemaRef{Value: propertyNames(ref.Value)}
+		elem.Value.Type = &openapi3.Types{openapi3.TypeString}
+	case r.target == eachValue || ref.Value.Type.Is(openapi3.TypeObject):
+		if ref.Value.AdditionalProperties.Schema == nil {
+			ref.Value.AdditionalProperties.Schema = &openapi3.SchemaRef{Value: openapi3.NewSchema()}
+		}
+		elem = ref.Value.AdditionalProperties.Schema
+	default:
+		if ref.Value.Items == nil {
+			ref.Value.Items = &openapi3.SchemaRef{Value: openapi3.NewSchema()}
+		}
+		elem = ref.Value.Items
+	}
+	for _, rule := range r.rules {
+		if err := describeRule(rule, o, name, nil, elem); err != nil {
 			return err
 		}
 	}
+	if r.target == eachKey {
+		// The type is implied: property names are strings.
+		elem.Value.Type = nil
+	}
 	return nil
 }
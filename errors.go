@@ -0,0 +1,88 @@
+package apivalidation
+
+import validation "github.com/go-ozzo/ozzo-validation/v4"
+
+// Error codes returned by the built-in rules. Every rule returns a
+// [validation.Error] whose Code is stable and whose Params carry the values
+// needed to render or translate the message (e.g. "min", "max", "allowed",
+// "actual"). Rules that wrap ozzo-validation keep ozzo's codes
+// (e.g. "validation_required", "validation_length_out_of_range").
+var (
+	// ErrInInvalid is the error that returns when a value is not one of the allowed values.
+	ErrInInvalid = validation.NewError("validation_in_invalid",
+		"must be one of {{range $i, $v := .allowed}}{{if $i}}, {{end}}'{{$v}}'{{end}} got '{{.actual}}'")
+	// ErrNotUnique is the error that returns when a collection contains duplicates.
+	ErrNotUnique = validation.NewError("validation_not_unique", "not unique")
+	// ErrDuplicateItem is the error that returns for an element that duplicates an earlier one.
+	ErrDuplicateItem = validation.NewError("validation_duplicate_item", "duplicates item {{.first}}")
+	// ErrNotSlice is the error that returns when a slice or array is expected.
+	ErrNotSlice = validation.NewError("validation_not_slice", "must be slice")
+	// ErrNotMap is the error that returns when a map is expected.
+	ErrNotMap = validation.NewError("validation_not_map", "must be a map")
+	// ErrKeyNotAllowed is the error that returns when a map key is not in the allowed list.
+	ErrKeyNotAllowed = validation.NewError("validation_key_not_allowed", "key '{{.key}}' not allowed")
+	// ErrNotInteger is the error that returns when a string cannot be parsed as a signed integer.
+	ErrNotInteger = validation.NewError("validation_not_integer", "must be int64")
+	// ErrNotUnsigned is the error that returns when a string cannot be parsed as an unsigned integer.
+	ErrNotUnsigned = validation.NewError("validation_not_unsigned", "must be uint64")
+	// ErrNotFloat is the error that returns when a string cannot be parsed as a float.
+	ErrNotFloat = validation.NewError("validation_not_float", "must be float64")
+	// ErrTypeMismatch is the error that returns when a value cannot be compared with a rule's bound.
+	ErrTypeMismatch = validation.NewError("validation_type_mismatch", "cannot compare {{.type}} with {{.want}}")
+	// ErrLengthUnsupported is the error that returns when the length of a value cannot be determined.
+	ErrLengthUnsupported = validation.NewError("validation_length_unsupported", "cannot get the length of {{.type}}")
+	// ErrNotIterable is the error that returns when a slice, array or map is expected.
+	ErrNotIterable = validation.NewError("validation_not_iterable", "must be an iterable (map, slice or array)")
+	// ErrNotString is the error that returns when a string is expected.
+	ErrNotString = validation.NewError("validation_not_string", "expected string, got {{.type}}")
+	// ErrAlphabeticRequired is the error that returns when a string has no alphabetic character.
+	ErrAlphabeticRequired = validation.NewError("validation_alphabetic_required", "must contain at least one alphabetic character")
+	// ErrCreditCardNumber is the error that returns when a string looks like a credit card number.
+	ErrCreditCardNumber = validation.NewError("validation_credit_card_number", "must not be a credit card number")
+	// ErrDecimalMax is the error that returns when a numeric string has too many decimal places.
+	ErrDecimalMax = validation.NewError("validation_decimal_max", "no more than {{.max}} decimals")
+	// ErrNotMultipleOf is the error that returns when a number is not a multiple of a [MultipleOf] factor.
+	ErrNotMultipleOf = validation.NewError("validation_not_multiple_of", "must be a multiple of {{.factor}}")
+	// ErrNotDecimal is the error that returns when a string is not a decimal number.
+	ErrNotDecimal = validation.NewError("validation_not_decimal", "must be a decimal number")
+	// ErrStringInvalid is the default error for rules created with [NewStringRule].
+	ErrStringInvalid = validation.NewError("validation_string_invalid", "must be valid")
+	// ErrRequiredIf is the error that returns when a [RequiredIf] field is empty.
+	ErrRequiredIf = validation.NewError("validation_required_if", "required when {{.field}} is {{.value}}")
+	// ErrRequiredWith is the error that returns when a [RequiredWith] field is empty.
+	ErrRequiredWith = validation.NewError("validation_required_with", "required when {{.field}} is set")
+	// ErrGreaterThanField is the error that returns when a value is not greater than another field.
+	ErrGreaterThanField = validation.NewError("validation_greater_than_field", "must be greater than {{.field}}")
+	// ErrDateAfterField is the error that returns when a date is not after the [DateRule.After] field.
+	ErrDateAfterField = validation.NewError("validation_date_after_field", "must be after {{.field}}")
+	// ErrMutuallyExclusive is the error that returns when more than one of a [MutuallyExclusive] set is present.
+	ErrMutuallyExclusive = validation.NewError("validation_mutually_exclusive",
+		"only one of {{range $i, $f := .fields}}{{if $i}}, {{end}}{{$f}}{{end}} may be set")
+	// ErrAnyOfInvalid is the error that returns when a value matches none of the [AnyOf] rules.
+	ErrAnyOfInvalid = validation.NewError("validation_any_of_invalid", "must match at least one of the alternatives")
+	// ErrOneOfInvalid is the error that returns when a value does not match exactly one of the [OneOf] rules.
+	ErrOneOfInvalid = validation.NewError("validation_one_of_invalid", "must match exactly one of the alternatives, matched {{.matched}}")
+	// ErrNotAllowed is the error that returns when a value matches a [Not] rule.
+	ErrNotAllowed = validation.NewError("validation_not_allowed", "is not allowed")
+	// ErrFormatInvalid is the error that returns when a string does not match its schema format.
+	ErrFormatInvalid = validation.NewError("validation_format_invalid", "must be a valid {{.format}}")
+	// ErrSchemaInvalid is the error that returns when a JSON value fails a schema keyword with no matching rule error.
+	ErrSchemaInvalid = validation.NewError("validation_schema_invalid", "must match the {{.keyword}} schema")
+)
+
+// withParams merges params into the params already carried by err.
+// Non-[validation.Error] errors are returned unchanged.
+func withParams(err error, params map[string]any) error {
+	verr, ok := err.(validation.Error)
+	if !ok {
+		return err
+	}
+	merged := make(map[string]any, len(verr.Params())+len(params))
+	for k, v := range verr.Params() {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return verr.SetParams(merged)
+}
@@ -0,0 +1,58 @@
+package apivalidation
+
+import (
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   Rule
+		value  any
+		code   string
+		params map[string]any
+	}{
+		{"min", Min(5), 3, "validation_min_greater_equal_than_required", map[string]any{"threshold": 5, "min": 5, "actual": 3}},
+		{"max string", Max(5), "7", "validation_max_less_equal_than_required", map[string]any{"threshold": 5, "max": 5, "actual": int64(7)}},
+		{"min not int", Min(5), "x", "validation_not_integer", map[string]any{"actual": "x"}},
+		{"min type mismatch", Min(0.5), 1, "validation_type_mismatch", map[string]any{"type": "int", "want": "float64"}},
+		{"length", Length(1, 3), "abcd", "validation_length_out_of_range", map[string]any{"min": 1, "max": 3, "actual": 4}},
+		{"in", In("a", "b"), "c", "validation_in_invalid", map[string]any{"allowed": []any{"a", "b"}, "actual": "c"}},
+		{"unique", Unique(func(i int) any { return 1 }, "all"), []int{1, 2}, "validation_not_unique", map[string]any{"desc": "all"}},
+		{"unique non slice", Unique(func(i int) any { return i }, ""), "x", "validation_not_slice", map[string]any{"type": "string"}},
+		{"key in", KeyIn("a"), map[string]int{"b": 1}, "validation_key_not_allowed", map[string]any{"key": "b", "allowed": []string{"a"}}},
+		{"key in non map", KeyIn("a"), "x", "validation_not_map", nil},
+		{"has alphabetic", HasAlphabetic(), "123", "validation_alphabetic_required", nil},
+		{"credit card", NonCreditCardNumber(), "4111 1111 1111 1111", "validation_credit_card_number", nil},
+		{"date", Date("2006-01-02"), "nope", "validation_date_invalid", map[string]any{"layout": "2006-01-02"}},
+		{"decimal max", NewStringRuleDecimalMax(2), "1.234", "validation_decimal_max", map[string]any{"max": uint(2)}},
+		{"string rule", NewStringRule(func(string) bool { return false }, "bad"), "x", "validation_string_invalid", nil},
+		{"string rule non string", NewStringRule(func(string) bool { return true }, "bad"), 1, "validation_not_string", map[string]any{"type": "int"}},
+		{"each non iterable", Each(Required), 1, "validation_not_iterable", nil},
+		{"required", Required, "", "validation_required", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate(tt.value)
+			require.Error(t, err)
+
+			var verr validation.Error
+			require.True(t, errors.As(err, &verr), "%T is not a validation.Error", err)
+			assert.Equal(t, tt.code, verr.Code())
+			if tt.params != nil {
+				assert.Equal(t, tt.params, verr.Params())
+			}
+		})
+	}
+}
+
+func TestErrorCodes_MessagesRender(t *testing.T) {
+	assert.Equal(t, "must be one of 'a', 'b' got 'c'", In("a", "b").Validate("c").Error())
+	assert.Equal(t, "key 'b' not allowed", KeyIn("a").Validate(map[string]int{"b": 1}).Error())
+	assert.Equal(t, "no more than 2 decimals", NewStringRuleDecimalMax(2).Validate("1.234").Error())
+}
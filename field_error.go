@@ -0,0 +1,87 @@
+package apivalidation
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// FieldError is a single, machine-readable validation failure.
+//
+// Pointer is an RFC 6901 JSON Pointer to the offending input (e.g.
+// "/items/3/sku"). Segments are the keys used by [ValidationErrors]: JSON tag
+// names (or Go field names when untagged), slice indexes, and map keys.
+// Embedded Ruler structs are flattened, so their fields appear directly under
+// the parent. An empty Pointer refers to the whole document. Errors from
+// [Struct] rules that are not attributed to a field (keyed by "") point to
+// the struct itself.
+type FieldError struct {
+	Pointer string         `json:"pointer"`
+	Code    string         `json:"code,omitempty"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// FieldErrors flattens an error returned by [Validate] (or any of the
+// decode-and-validate helpers) into a list of [FieldError], sorted by pointer.
+// Code and Params are taken from [validation.Error] values; other errors only
+// carry a Message. Returns nil if err is nil.
+func FieldErrors(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+	var out []FieldError
+	collectFieldErrors("", err, &out)
+	return out
+}
+
+func collectFieldErrors(pointer string, err error, out *[]FieldError) {
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		keys := make([]string, 0, len(errs))
+		for k := range errs {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) })
+		for _, k := range keys {
+			if errs[k] == nil {
+				continue
+			}
+			p := pointer
+			if k != "" {
+				p = pointerAppend(pointer, k)
+			}
+			collectFieldErrors(p, errs[k], out)
+		}
+		return
+	}
+
+	fe := FieldError{Pointer: pointer, Message: err.Error()}
+	var verr validation.Error
+	if errors.As(err, &verr) {
+		fe.Code = verr.Code()
+		fe.Params = verr.Params()
+		fe.Message = verr.Error()
+	}
+	*out = append(*out, fe)
+}
+
+// pointerAppend appends key as a JSON Pointer segment, escaping "~" and "/".
+func pointerAppend(pointer, key string) string {
+	key = strings.ReplaceAll(key, "~", "~0")
+	key = strings.ReplaceAll(key, "/", "~1")
+	return pointer + "/" + key
+}
+
+// lessKey orders slice indexes numerically and everything else lexically.
+func lessKey(a, b string) bool {
+	ai, aErr := strconv.Atoi(a)
+	bi, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		return ai < bi
+	}
+	return a < b
+}
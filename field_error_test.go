@@ -0,0 +1,87 @@
+package apivalidation_test
+
+import (
+	"errors"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type feLine struct {
+	SKU string `json:"sku"`
+}
+
+func (l *feLine) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&l.SKU, v.Required),
+	}
+}
+
+type feOrder struct {
+	valBase
+	Items []feLine          `json:"items"`
+	ByKey map[string]feLine `json:"by_key"`
+}
+
+func (o *feOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&o.valBase),
+		v.Field(&o.Items),
+		v.Field(&o.ByKey),
+	}
+}
+
+func TestFieldErrors_Nil(t *testing.T) {
+	assert.Nil(t, v.FieldErrors(nil))
+}
+
+func TestFieldErrors_Pointers(t *testing.T) {
+	o := feOrder{
+		Items: []feLine{{SKU: "a"}, {}, {SKU: "b"}, {}},
+		ByKey: map[string]feLine{"x/y": {}},
+	}
+	err := v.Validate(&o)
+	require.Error(t, err)
+
+	fes := v.FieldErrors(err)
+	pointers := make([]string, len(fes))
+	for i, fe := range fes {
+		pointers[i] = fe.Pointer
+	}
+	assert.Equal(t, []string{"/ID", "/by_key/x~1y/sku", "/items/1/sku", "/items/3/sku"}, pointers)
+
+	assert.Equal(t, "validation_required", fes[2].Code)
+	assert.Equal(t, "cannot be blank", fes[2].Message)
+}
+
+func TestFieldErrors_NumericOrder(t *testing.T) {
+	items := make([]valItem, 11)
+	for i := range items {
+		items[i].Name = "ok"
+	}
+	items[2].Name = ""
+	items[10].Name = ""
+
+	fes := v.FieldErrors(v.Validate(&items))
+	require.Len(t, fes, 2)
+	assert.Equal(t, "/2/Name", fes[0].Pointer)
+	assert.Equal(t, "/10/Name", fes[1].Pointer)
+}
+
+func TestFieldErrors_Params(t *testing.T) {
+	item := valItem{Name: "this name is far too long to pass the length rule of fifty runes"}
+	fes := v.FieldErrors(v.Validate(&item))
+	require.Len(t, fes, 1)
+	assert.Equal(t, "/Name", fes[0].Pointer)
+	assert.Equal(t, "validation_length_out_of_range", fes[0].Code)
+	assert.Equal(t, map[string]any{"min": 1, "max": 50, "actual": 64}, fes[0].Params)
+	assert.Equal(t, "the length must be between 1 and 50", fes[0].Message)
+}
+
+func TestFieldErrors_PlainError(t *testing.T) {
+	fes := v.FieldErrors(errors.New("boom"))
+	require.Len(t, fes, 1)
+	assert.Equal(t, v.FieldError{Pointer: "", Message: "boom"}, fes[0])
+}
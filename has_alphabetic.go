@@ -25,7 +25,7 @@ func NonCreditCardNumber() Rule {
 }
 
 func (r hasAlphabetic) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
-	ref.Value.Description += "Must contain at least one alphabetic character. "
+	ref.Value.Description += Messages.Describe("describe_alphabetic_required", "Must contain at least one alphabetic character.", nil) + " "
 	return nil
 }
 
@@ -37,7 +37,7 @@ var (
 func (r hasAlphabetic) Validate(value any) error {
 	v, ok := value.(string)
 	if !ok {
-		return fmt.Errorf("expected string, got %T", value)
+		return ErrNotString.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
 	}
 
 	v = strings.TrimSpace(v)
@@ -51,9 +51,9 @@ func (r hasAlphabetic) Validate(value any) error {
 			if len(nr) != creditCardNumberLength {
 				return nil
 			}
-			return fmt.Errorf("must not be a credit card number")
+			return ErrCreditCardNumber
 		}
-		return fmt.Errorf("must contain at least one alphabetic character")
+		return ErrAlphabeticRequired
 	}
 	return nil
 }
@@ -1,21 +1,15 @@
 package apivalidation
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/getkin/kin-openapi/openapi3"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 // In returns a validation rule that checks if a value is one of the allowed values.
+// On failure it returns [ErrInInvalid] with the "allowed" and "actual" params.
 func In(values ...any) Rule {
-	want := make([]string, len(values))
-	for i := range values {
-		want[i] = fmt.Sprintf("'%v'", values[i])
-	}
 	return &inRule{
-		validation.In(values...).Error(fmt.Sprintf("must be one of %s", strings.Join(want, ", "))),
+		validation.In(values...),
 		values,
 	}
 }
@@ -27,9 +21,8 @@ type inRule struct {
 }
 
 func (r *inRule) Validate(value any) error {
-	err := r.InRule.Validate(value)
-	if err != nil {
-		return fmt.Errorf("%s got '%v'", err, value)
+	if err := r.InRule.Validate(value); err != nil {
+		return ErrInInvalid.SetParams(map[string]any{"allowed": r.values, "actual": value})
 	}
 	return nil
 }
@@ -0,0 +1,29 @@
+// Package currency holds the ISO 4217 checks shared by the is.CurrencyCode
+// rule and the apivalidation Money rule.
+package currency
+
+import "github.com/asaskevich/govalidator"
+
+// Valid reports whether code is an ISO 4217 currency code.
+func Valid(code string) bool {
+	return govalidator.IsISO4217(code)
+}
+
+// MinorUnits returns the number of decimal places of the ISO 4217 currency
+// code. ok is false for invalid codes and codes without a minor unit.
+func MinorUnits(code string) (units int, ok bool) {
+	if !Valid(code) {
+		return 0, false
+	}
+	switch code {
+	case "XAG", "XAU", "XBA", "XBB", "XBC", "XBD", "XDR", "XPD", "XPT", "XSU", "XTS", "XUA", "XXX":
+		return 0, false
+	case "BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG", "RWF", "UGX", "UYI", "VND", "VUV", "XAF", "XOF", "XPF":
+		return 0, true
+	case "BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND":
+		return 3, true
+	case "CLF", "UYW":
+		return 4, true
+	}
+	return 2, true
+}
@@ -10,6 +10,7 @@ import (
 	"unicode"
 
 	"github.com/Gobd/apivalidation"
+	"github.com/Gobd/apivalidation/internal/currency"
 	"github.com/asaskevich/govalidator"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
@@ -204,8 +205,8 @@ var (
 	// CountryCode2 = apivalidation.NewStringRuleWithError(govalidator.IsISO3166Alpha2, ErrCountryCode2)
 	// // CountryCode3 validates if a string is a valid ISO3166 Alpha 3 country code
 	// CountryCode3 = apivalidation.NewStringRuleWithError(govalidator.IsISO3166Alpha3, ErrCountryCode3)
-	// // CurrencyCode validates if a string is a valid IsISO4217 currency code.
-	// CurrencyCode = apivalidation.NewStringRuleWithError(govalidator.IsISO4217, ErrCurrencyCode)
+	// CurrencyCode validates if a string is a valid ISO 4217 currency code.
+	CurrencyCode = apivalidation.NewStringRuleWithError(currency.Valid, ErrCurrencyCode, "must be ISO 4217 currency code")
 	// // DialString validates if a string is a valid dial string that can be passed to Dial()
 	// DialString = apivalidation.NewStringRuleWithError(govalidator.IsDialString, ErrDialString)
 	// // MAC validates if a string is a MAC address
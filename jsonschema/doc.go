@@ -0,0 +1,9 @@
+// Package jsonschema generates standalone JSON Schema (draft 2020-12)
+// documents from types that implement [apivalidation.Ruler], using the same
+// rule descriptions as the openapi package. Nested named types go to $defs.
+//
+//	doc, err := jsonschema.For(Order{}, jsonschema.Options{BaseURI: "https://schemas.example.com/"})
+//
+// [WriteFiles] writes one document per type to a directory, for publishing
+// to a schema registry.
+package jsonschema
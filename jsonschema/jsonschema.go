@@ -0,0 +1,266 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	av "github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Dialect is the $schema of generated documents.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+const componentsPrefix = "#/components/schemas/"
+
+// Options configures [For] and [WriteFiles].
+type Options struct {
+	// BaseURI prefixes the $id of each document, which is the type name
+	// with a .json extension (e.g. "https://schemas.example.com/Order.json").
+	// Documents have no $id if BaseURI is empty.
+	BaseURI string
+	// Strict disallows additional properties (see [apivalidation.Strict]).
+	Strict bool
+}
+
+func (o Options) schemaOptions(c *av.Components) []av.Option {
+	opts := []av.Option{av.WithComponents(c), av.OpenAPI31()}
+	if o.Strict {
+		opts = append(opts, av.Strict())
+	}
+	return opts
+}
+
+// Document is a JSON Schema document.
+type Document map[string]any
+
+// For generates the JSON Schema document for value's type. Named types with
+// rules are emitted once under $defs and referenced with $ref; if value's
+// own type is one, its schema is the document root.
+func For(value any, opts Options) (Document, error) {
+	schemas := openapi3.Schemas{}
+	ref, err := av.NewSchemaRefForValue(value, opts.schemaOptions(av.NewComponents(schemas))...)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := toDefs(schemas)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimPrefix(ref.Ref, componentsPrefix)
+	if ref.Ref == "" {
+		name = typeName(value)
+	}
+	root, err := toValue(ref)
+	if err != nil {
+		return nil, err
+	}
+	return newDocument(root.(map[string]any), name, defs, opts), nil
+}
+
+// WriteFiles writes a document for each value's type, and for every named
+// type with rules they contain, to dir as {Name}.json. Each document is
+// self-contained: the types it refers to are repeated in its $defs.
+func WriteFiles(dir string, opts Options, values ...any) error {
+	schemas := openapi3.Schemas{}
+	schemaOpts := opts.schemaOptions(av.NewComponents(schemas))
+	docs := map[string]Document{}
+	for _, value := range values {
+		ref, err := av.NewSchemaRefForValue(value, schemaOpts...)
+		if err != nil {
+			return err
+		}
+		if ref.Ref != "" {
+			continue // written with the other components below
+		}
+		name := typeName(value)
+		if name == "" {
+			return fmt.Errorf("jsonschema: cannot name a file for unnamed type %T", value)
+		}
+		root, err := toValue(ref)
+		if err != nil {
+			return err
+		}
+		docs[name] = Document(root.(map[string]any))
+	}
+	defs, err := toDefs(schemas)
+	if err != nil {
+		return err
+	}
+	for name, root := range docs {
+		docs[name] = newDocument(root, name, defs, opts)
+	}
+	for name := range defs {
+		docs[name] = newDocument(map[string]any{"$ref": componentsPrefix + name}, name, defs, opts)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, doc := range docs {
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), append(b, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDocument builds the document for root, named name. A root that is a
+// $ref to a definition is replaced by the definition.
+func newDocument(root map[string]any, name string, defs map[string]any, opts Options) Document {
+	if r, ok := root["$ref"].(string); ok && len(root) == 1 && strings.HasPrefix(r, componentsPrefix) {
+		name = strings.TrimPrefix(r, componentsPrefix)
+		root = deepCopy(defs[name]).(map[string]any)
+	}
+	doc := Document(rewriteRefs(root, name).(map[string]any))
+
+	used := map[string]bool{}
+	collectRefs(map[string]any(doc), defs, name, used)
+	if len(used) > 0 {
+		docDefs := map[string]any{}
+		for def := range used {
+			docDefs[def] = rewriteRefs(deepCopy(defs[def]), name)
+		}
+		doc["$defs"] = docDefs
+	}
+
+	doc["$schema"] = Dialect
+	if opts.BaseURI != "" && name != "" {
+		doc["$id"] = opts.BaseURI + name + ".json"
+	}
+	return doc
+}
+
+// collectRefs adds the definitions v refers to, directly or through other
+// definitions, to used. self is the document root's own definition.
+func collectRefs(v any, defs map[string]any, self string, used map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if r, ok := child.(string); ok && k == "$ref" && strings.HasPrefix(r, "#/$defs/") {
+				def := strings.TrimPrefix(r, "#/$defs/")
+				if def != self && !used[def] {
+					used[def] = true
+					collectRefs(rewriteRefs(deepCopy(defs[def]), self), defs, self, used)
+				}
+				continue
+			}
+			collectRefs(child, defs, self, used)
+		}
+	case []any:
+		for _, child := range v {
+			collectRefs(child, defs, self, used)
+		}
+	}
+}
+
+// rewriteRefs points component references of the schema v to $defs, or to
+// the root for self, and drops the OpenAPI-only discriminator keyword of
+// oneOf and anyOf schemas. Maps of named subschemas, such as properties, are
+// walked by name, so a property named "discriminator" or "$ref" is kept, and
+// data keywords such as enum and default are left as they are.
+func rewriteRefs(v any, self string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v["oneOf"] != nil || v["anyOf"] != nil {
+			delete(v, "discriminator")
+		}
+		for k, child := range v {
+			switch k {
+			case "$ref":
+				if r, ok := child.(string); ok && strings.HasPrefix(r, componentsPrefix) {
+					def := strings.TrimPrefix(r, componentsPrefix)
+					if def == self {
+						v[k] = "#"
+					} else {
+						v[k] = "#/$defs/" + def
+					}
+				}
+			case "properties", "patternProperties", "dependentSchemas", "$defs":
+				if named, ok := child.(map[string]any); ok {
+					for name, sub := range named {
+						named[name] = rewriteRefs(sub, self)
+					}
+				}
+			case "enum", "const", "default", "example", "examples":
+			default:
+				v[k] = rewriteRefs(child, self)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = rewriteRefs(child, self)
+		}
+	}
+	return v
+}
+
+// toDefs converts the component schemas to plain JSON values.
+func toDefs(schemas openapi3.Schemas) (map[string]any, error) {
+	defs := make(map[string]any, len(schemas))
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def, err := toValue(schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		defs[name] = def
+	}
+	return defs, nil
+}
+
+// toValue round-trips v through JSON into maps and slices.
+func toValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = deepCopy(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = deepCopy(child)
+		}
+		return out
+	}
+	return v
+}
+
+// typeName returns the name of value's type, dereferencing pointers.
+func typeName(value any) string {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
@@ -0,0 +1,149 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/Gobd/apivalidation/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+func (a *Address) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&a.City, v.Required),
+		v.Field(&a.Zip, v.Length(5, 5)),
+	}
+}
+
+type Customer struct {
+	Name     string    `json:"name"`
+	Nickname *string   `json:"nickname"`
+	Address  Address   `json:"address"`
+	Others   []Address `json:"others"`
+	Referrer *Customer `json:"referrer"`
+}
+
+func (c *Customer) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&c.Name, v.Required, v.Describe("full name")),
+	}
+}
+
+type envelope struct {
+	Customer Customer `json:"customer"`
+}
+
+func TestFor(t *testing.T) {
+	doc, err := jsonschema.For(Customer{}, jsonschema.Options{BaseURI: "https://schemas.example.com/"})
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonschema.Dialect, doc["$schema"])
+	assert.Equal(t, "https://schemas.example.com/Customer.json", doc["$id"])
+	assert.Equal(t, []any{"name"}, doc["required"])
+
+	props := doc["properties"].(map[string]any)
+	assert.Equal(t, "full name", props["name"].(map[string]any)["description"])
+	assert.Equal(t, []any{"string", "null"}, props["nickname"].(map[string]any)["type"])
+	assert.Equal(t, "#/$defs/Address", props["address"].(map[string]any)["$ref"])
+	assert.Equal(t, "#/$defs/Address", props["others"].(map[string]any)["items"].(map[string]any)["$ref"])
+
+	defs := doc["$defs"].(map[string]any)
+	assert.Len(t, defs, 1, "the root type is not repeated in $defs")
+	address := defs["Address"].(map[string]any)
+	assert.Equal(t, []any{"city"}, address["required"])
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "#/components/schemas/")
+	assert.NotContains(t, string(b), "nullable")
+}
+
+func TestFor_Unnamed(t *testing.T) {
+	doc, err := jsonschema.For(&envelope{}, jsonschema.Options{})
+	require.NoError(t, err)
+
+	assert.NotContains(t, doc, "$id")
+	props := doc["properties"].(map[string]any)
+	assert.Equal(t, "#/$defs/Customer", props["customer"].(map[string]any)["$ref"])
+	defs := doc["$defs"].(map[string]any)
+	assert.Contains(t, defs, "Customer")
+	assert.Contains(t, defs, "Address")
+}
+
+func TestFor_Strict(t *testing.T) {
+	doc, err := jsonschema.For(Address{}, jsonschema.Options{Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, false, doc["additionalProperties"])
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	err := jsonschema.WriteFiles(dir, jsonschema.Options{BaseURI: "https://schemas.example.com/"}, Customer{}, envelope{})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"Address.json", "Customer.json", "envelope.json"}, names)
+
+	b, err := os.ReadFile(filepath.Join(dir, "Customer.json"))
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, "https://schemas.example.com/Customer.json", doc["$id"])
+	assert.Equal(t, "#", doc["properties"].(map[string]any)["referrer"].(map[string]any)["$ref"])
+	assert.Contains(t, doc["$defs"], "Address")
+}
+
+type payment interface{ isPayment() }
+
+type Card struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+func (Card) isPayment() {}
+
+type Wire struct {
+	Type string `json:"type"`
+	IBAN string `json:"iban"`
+}
+
+func (Wire) isPayment() {}
+
+var _ = v.NewUnion[payment]("type").Variant("card", Card{}).Variant("wire", Wire{})
+
+type routed struct {
+	Discriminator string                   `json:"discriminator"`
+	Payment       v.Discriminated[payment] `json:"payment"`
+}
+
+func (r *routed) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&r.Discriminator, v.Required),
+	}
+}
+
+func TestFor_Discriminator(t *testing.T) {
+	doc, err := jsonschema.For(routed{}, jsonschema.Options{})
+	require.NoError(t, err)
+
+	props := doc["properties"].(map[string]any)
+	assert.Contains(t, props, "discriminator", "a property may be named discriminator")
+	assert.Equal(t, []any{"discriminator"}, doc["required"])
+	payment := props["payment"].(map[string]any)
+	assert.NotEmpty(t, payment["oneOf"])
+	assert.NotContains(t, payment, "discriminator", "the OpenAPI keyword is dropped")
+}
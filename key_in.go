@@ -1,50 +1,83 @@
 package apivalidation
 
 import (
-	"encoding/json"
 	"fmt"
-	"strings"
+	"reflect"
+	"slices"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
-// KeyIn ensures that the keys of a map are in the allowed values
+// KeyIn ensures that the keys of a map are in the allowed values. Keys are
+// compared in their JSON form, so non-string keys (e.g. ints) are formatted
+// as strings. The allowed values are documented as the enum of
+// propertyNames (x-propertyNames in OpenAPI 3.0).
+// On failure it returns [ErrKeyNotAllowed] with the "key" and "allowed"
+// params for the first key not allowed, in sorted order, or [ErrNotMap] for
+// non-map values.
 func KeyIn(values ...string) Rule {
 	return &keyInRule{values}
 }
 
+// KeyPattern ensures that the keys of a map match the regular expression
+// expr, documented as the pattern of propertyNames. Errors are keyed by the
+// offending key. It panics if expr is not a valid [Pattern].
+//
+//	v.Field(&o.Labels, v.KeyPattern(`^[a-z][a-z0-9_]*$`))
+func KeyPattern(expr string) Rule {
+	return KeyRules(Pattern(expr))
+}
+
+// KeyRules applies rules to each key of a map. It is the same as [EachKey].
+func KeyRules(rules ...Rule) Rule {
+	return EachKey(rules...)
+}
+
 type keyInRule struct {
 	values []string
 }
 
 func (r *keyInRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
-	if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
-		ref.Value.Description += " "
+	names := propertyNames(ref.Value)
+	names.Enum = make([]any, len(r.values))
+	for i, v := range r.values {
+		names.Enum[i] = v
 	}
-	ref.Value.Description += fmt.Sprintf("keys must be in (%s)", strings.Join(r.values, ","))
 	return nil
 }
 
 func (r keyInRule) Validate(value any) error {
-	validKeys := map[string]bool{}
-	for _, v := range r.values {
-		validKeys[v] = true
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
 	}
-
-	var jsonmap map[string]any
-	b, err := json.Marshal(value)
-	if err != nil {
-		return err
+	if rv.Kind() != reflect.Map {
+		return ErrNotMap.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
 	}
-	err = json.Unmarshal(b, &jsonmap)
-	if err != nil {
-		return err
+	keys := make([]string, 0, rv.Len())
+	for _, key := range rv.MapKeys() {
+		k := fmt.Sprintf("%v", key.Interface())
+		if !slices.Contains(r.values, k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
 	}
+	slices.Sort(keys)
+	return ErrKeyNotAllowed.SetParams(map[string]any{"key": keys[0], "allowed": r.values})
+}
 
-	for k := range jsonmap {
-		if !validKeys[k] {
-			return fmt.Errorf("key '%s' not allowed", k)
-		}
+// propertyNames returns the x-propertyNames schema of s, adding an empty one
+// if there is none.
+func propertyNames(s *openapi3.Schema) *openapi3.Schema {
+	if names, ok := s.Extensions["x-propertyNames"].(*openapi3.Schema); ok {
+		return names
 	}
-	return nil
+	names := openapi3.NewSchema()
+	setExtension(s, "x-propertyNames", names)
+	return names
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -60,6 +61,18 @@ func TestKeyInt(t *testing.T) {
 			allowed:     []string{"a"},
 			expectError: false,
 		},
+		{
+			name:        "int keys",
+			in:          map[int]string{1: "a"},
+			allowed:     []string{"1"},
+			expectError: false,
+		},
+		{
+			name:        "struct",
+			in:          struct{ A string }{A: "b"},
+			allowed:     []string{"A"},
+			expectError: true,
+		},
 		{
 			name:        "non map",
 			in:          "a",
@@ -79,3 +92,28 @@ func TestKeyInt(t *testing.T) {
 		})
 	}
 }
+
+type keyPatternTest struct {
+	Labels map[string]int `json:"labels"`
+}
+
+func (k *keyPatternTest) Rules() []*apivalidation.FieldRules {
+	return []*apivalidation.FieldRules{
+		apivalidation.Field(&k.Labels, apivalidation.KeyPattern(`^[a-z]+$`), apivalidation.KeyRules(apivalidation.Length(1, 3))),
+	}
+}
+
+func TestKeyPattern(t *testing.T) {
+	assert.NoError(t, apivalidation.Validate(&keyPatternTest{Labels: map[string]int{"env": 1}}))
+
+	errs := apivalidation.FieldErrors(apivalidation.Validate(&keyPatternTest{Labels: map[string]int{"env": 1, "Tier": 2}}))
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "/labels/Tier", errs[0].Pointer)
+	}
+
+	ref, err := apivalidation.NewSchemaRefForValue(keyPatternTest{}, apivalidation.OpenAPI31())
+	assert.NoError(t, err)
+	names := ref.Value.Properties["labels"].Value.Extensions["propertyNames"].(*openapi3.Schema)
+	assert.Equal(t, `^[a-z]+$`, names.Pattern)
+	assert.Equal(t, uint64(3), *names.MaxLength)
+}
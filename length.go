@@ -1,6 +1,10 @@
 package apivalidation
 
 import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
@@ -11,6 +15,8 @@ type lengthRule struct {
 }
 
 // Length returns a validation rule that checks if a string's rune length is within the specified range.
+// On failure it returns one of ozzo's validation_length_* errors with the
+// "min", "max" and "actual" params.
 func Length(lo, hi int) Rule {
 	return &lengthRule{
 		validation.RuneLength(lo, hi),
@@ -19,10 +25,118 @@ func Length(lo, hi int) Rule {
 	}
 }
 
+func (r *lengthRule) Validate(value any) error {
+	err := r.LengthRule.Validate(value)
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(validation.Error); !ok {
+		return ErrLengthUnsupported.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+	}
+	value, _ = validation.Indirect(value)
+	actual := 0
+	if s, ok := value.(string); ok {
+		actual = utf8.RuneCountInString(s)
+	} else if rv := reflect.ValueOf(value); rv.IsValid() {
+		actual = rv.Len()
+	}
+	return withParams(err, map[string]any{"actual": actual})
+}
+
+// Describe sets minLength and maxLength, or minItems and maxItems for
+// arrays and minProperties and maxProperties for objects. A maximum of 0
+// means no upper bound, as in Validate.
 func (r *lengthRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
-	fmin := float64(r.min)
-	fmax := float64(r.max)
-	ref.Value.Max = &fmax
-	ref.Value.Min = &fmin
+	lo, hi := uint64(r.min), uint64(r.max)
+	var max *uint64
+	if r.max > 0 {
+		max = &hi
+	}
+	switch {
+	case ref.Value.Type.Is(openapi3.TypeArray):
+		ref.Value.MinItems, ref.Value.MaxItems = lo, max
+	case ref.Value.Type.Is(openapi3.TypeObject):
+		ref.Value.MinProps, ref.Value.MaxProps = lo, max
+	default:
+		ref.Value.MinLength, ref.Value.MaxLength = lo, max
+	}
+	return nil
+}
+
+// sizeRule checks the number of elements of a slice, array or map.
+type sizeRule struct {
+	bound int
+	max   bool // bound is a maximum rather than a minimum
+	props bool // maps rather than slices and arrays
+}
+
+// MinItems returns a validation rule that checks a slice or array has at
+// least n elements. Nil slices pass; combine with [Required] to reject them.
+// On failure it returns ozzo's validation_length_too_short error with the
+// "min" and "actual" params.
+func MinItems(n int) Rule {
+	return sizeRule{bound: n}
+}
+
+// MaxItems returns a validation rule that checks a slice or array has at
+// most n elements. On failure it returns ozzo's validation_length_too_long
+// error with the "max" and "actual" params.
+func MaxItems(n int) Rule {
+	return sizeRule{bound: n, max: true}
+}
+
+// MinProperties returns a validation rule that checks a map has at least n
+// entries. Nil maps pass; combine with [Required] to reject them. On
+// failure it returns ozzo's validation_length_too_short error with the
+// "min" and "actual" params.
+func MinProperties(n int) Rule {
+	return sizeRule{bound: n, props: true}
+}
+
+// MaxProperties returns a validation rule that checks a map has at most n
+// entries. On failure it returns ozzo's validation_length_too_long error
+// with the "max" and "actual" params.
+func MaxProperties(n int) Rule {
+	return sizeRule{bound: n, max: true, props: true}
+}
+
+func (r sizeRule) Validate(value any) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch kind := rv.Kind(); {
+	case r.props && kind != reflect.Map:
+		return ErrNotMap.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+	case !r.props && kind != reflect.Slice && kind != reflect.Array:
+		return ErrNotSlice.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+	case (kind == reflect.Slice || kind == reflect.Map) && rv.IsNil():
+		return nil
+	}
+	n := rv.Len()
+	switch {
+	case r.max && n > r.bound:
+		return validation.ErrLengthTooLong.SetParams(map[string]any{"max": r.bound, "actual": n})
+	case !r.max && n < r.bound:
+		return validation.ErrLengthTooShort.SetParams(map[string]any{"min": r.bound, "actual": n})
+	}
+	return nil
+}
+
+func (r sizeRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	n := uint64(r.bound)
+	switch {
+	case r.props && r.max:
+		ref.Value.MaxProps = &n
+	case r.props:
+		ref.Value.MinProps = n
+	case r.max:
+		ref.Value.MaxItems = &n
+	default:
+		ref.Value.MinItems = n
+	}
 	return nil
 }
@@ -3,6 +3,7 @@ package apivalidation
 import (
 	"testing"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,3 +18,20 @@ func TestLength(t *testing.T) {
 	err = r.Validate("Richard-Breslau-Straãƒæ'ã†Â€™Ãƒâ€ Ã¢Â‚¬Â„¢Ãƒæ'ã¢Â‚¬Â¦Ãƒâ€Šã‚Â¸E 21234567890abcdefghijklmnopqrstuvwxyz") // 101
 	require.NotNil(t, err)
 }
+
+func TestSizeRules(t *testing.T) {
+	require.NoError(t, MinItems(1).Validate([]int{1}))
+	require.NoError(t, MinItems(1).Validate([]int(nil)), "nil slices pass")
+	require.NoError(t, MaxItems(2).Validate([2]string{}))
+	require.NoError(t, MaxProperties(1).Validate(map[string]int{"a": 1}))
+
+	err := MinItems(2).Validate(&[]int{1})
+	require.Equal(t, map[string]any{"min": 2, "actual": 1}, err.(validation.Error).Params())
+	require.Equal(t, validation.ErrLengthTooShort.Code(), err.(validation.Error).Code())
+
+	err = MaxProperties(1).Validate(map[string]int{"a": 1, "b": 2})
+	require.Equal(t, validation.ErrLengthTooLong.Code(), err.(validation.Error).Code())
+
+	require.Equal(t, ErrNotSlice.Code(), MinItems(1).Validate("abc").(validation.Error).Code())
+	require.Equal(t, ErrNotMap.Code(), MinProperties(1).Validate([]int{1}).(validation.Error).Code())
+}
@@ -1,7 +1,6 @@
 package apivalidation
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -14,38 +13,86 @@ type thresholdRule struct {
 	validation.ThresholdRule
 	threshold any
 	min       bool
+	exclusive bool
 }
 
 // Min returns a validation rule that checks if a value is greater than or equal to the specified minimum.
+// Numeric strings and [encoding/json.Number] are compared with float and string thresholds as exact decimals,
+// so Min("0.01") accepts "0.01" but not "0.009".
+// On failure it returns ozzo's validation_min_greater_equal_than_required error
+// with the "threshold", "min" and "actual" params.
 func Min(threshold any) Rule {
 	return thresholdRule{
-		validation.Min(threshold),
-		threshold,
-		true,
+		ThresholdRule: validation.Min(threshold),
+		threshold:     threshold,
+		min:           true,
 	}
 }
 
 // Max returns a validation rule that checks if a value is less than or equal to the specified maximum.
+// On failure it returns ozzo's validation_max_less_equal_than_required error
+// with the "threshold", "max" and "actual" params.
 func Max(threshold any) Rule {
 	return thresholdRule{
-		validation.Max(threshold),
-		threshold,
-		false,
+		ThresholdRule: validation.Max(threshold),
+		threshold:     threshold,
 	}
 }
 
-func (r thresholdRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
-	if ref.Value.Type.Is(openapi3.TypeString) {
-		ref.Value.Format = fmt.Sprintf("%T", r.threshold)
+// ExclusiveMin returns a validation rule that checks if a value is greater than the specified minimum,
+// documented as exclusiveMinimum. It accepts the same values as [Min].
+// On failure it returns ozzo's validation_min_greater_than_required error
+// with the "threshold", "min" and "actual" params.
+func ExclusiveMin(threshold any) Rule {
+	return thresholdRule{
+		ThresholdRule: validation.Min(threshold).Exclusive(),
+		threshold:     threshold,
+		min:           true,
+		exclusive:     true,
 	}
-	f, err := getFloat(r.threshold)
-	if err != nil {
-		return err
+}
+
+// ExclusiveMax returns a validation rule that checks if a value is less than the specified maximum,
+// documented as exclusiveMaximum. It accepts the same values as [Max].
+// On failure it returns ozzo's validation_max_less_than_required error
+// with the "threshold", "max" and "actual" params.
+func ExclusiveMax(threshold any) Rule {
+	return thresholdRule{
+		ThresholdRule: validation.Max(threshold).Exclusive(),
+		threshold:     threshold,
+		exclusive:     true,
+	}
+}
+
+func (r thresholdRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	var f float64
+	if s, ok := r.threshold.(string); ok {
+		bound, err := toRat(s)
+		if err != nil {
+			return fmt.Errorf("threshold %q is not a decimal number", s)
+		}
+		f, _ = bound.Float64()
+		if ref.Value.Type.Is(openapi3.TypeString) {
+			ref.Value.Format = "decimal"
+		}
+	} else {
+		var err error
+		if f, err = getFloat(r.threshold); err != nil {
+			return err
+		}
+		// Strings compared with a float threshold are parsed as exact
+		// decimals; with an integer threshold they must be integers, which
+		// no string format names.
+		if ref.Value.Type.Is(openapi3.TypeString) && reflect.ValueOf(r.threshold).CanFloat() {
+			ref.Value.Format = "decimal"
+		}
 	}
 	if r.min {
 		ref.Value.Min = &f
+		ref.Value.ExclusiveMin = r.exclusive
 	} else {
 		ref.Value.Max = &f
+		ref.Value.ExclusiveMax = r.exclusive
 	}
 	return nil
 }
@@ -69,8 +116,11 @@ func (r thresholdRule) Validate(value any) error {
 		return nil
 	}
 
+	if reflect.ValueOf(r.threshold).Kind() == reflect.String {
+		return r.validateDecimal(value)
+	}
 	if reflect.ValueOf(value).Kind() != reflect.String {
-		return r.ThresholdRule.Validate(value)
+		return r.thresholdError(r.ThresholdRule.Validate(value), value)
 	}
 
 	// Handle json.Number and other types
@@ -78,25 +128,84 @@ func (r thresholdRule) Validate(value any) error {
 		value = v.String()
 	}
 
+	s := reflect.ValueOf(value).String()
 	var err error
 	rv := reflect.ValueOf(r.threshold)
 	switch rv.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		value, err = strconv.ParseInt(value.(string), 10, 64)
+		value, err = strconv.ParseInt(s, 10, 64)
 		if err != nil {
-			return errors.New("must be int64")
+			return ErrNotInteger.SetParams(map[string]any{"actual": s})
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		value, err = strconv.ParseUint(value.(string), 10, 64)
+		value, err = strconv.ParseUint(s, 10, 64)
 		if err != nil {
-			return errors.New("must be uint64")
+			return ErrNotUnsigned.SetParams(map[string]any{"actual": s})
 		}
 	case reflect.Float32, reflect.Float64:
-		value, err = strconv.ParseFloat(value.(string), 64)
+		// Compare decimals exactly: parsing s as a float64 would round it to
+		// the threshold (e.g. "0.1000000000000000001" to 0.1). Other forms
+		// strconv.ParseFloat accepts, such as "Inf" or hex floats, are
+		// compared as floats.
+		if decimalString.MatchString(s) {
+			return r.validateDecimal(s)
+		}
+		value, err = strconv.ParseFloat(s, 64)
 		if err != nil {
-			return errors.New("must be float64")
+			return ErrNotFloat.SetParams(map[string]any{"actual": s})
 		}
 	}
 
-	return r.ThresholdRule.Validate(value)
+	return r.thresholdError(r.ThresholdRule.Validate(value), value)
+}
+
+// validateDecimal compares value with the threshold as exact decimals. It
+// is used for string thresholds, and for numeric strings compared with float
+// thresholds.
+func (r thresholdRule) validateDecimal(value any) error {
+	n, err := toRat(value)
+	if err != nil {
+		return err
+	}
+	bound, err := toRat(r.threshold)
+	if err != nil {
+		return ErrTypeMismatch.SetParams(map[string]any{
+			"type": fmt.Sprintf("%T", value),
+			"want": fmt.Sprintf("%T", r.threshold),
+		})
+	}
+	cmp := n.Cmp(bound)
+	var verr validation.Error
+	switch {
+	case r.min && r.exclusive && cmp <= 0:
+		verr = validation.ErrMinGreaterThanRequired
+	case r.min && !r.exclusive && cmp < 0:
+		verr = validation.ErrMinGreaterEqualThanRequired
+	case !r.min && r.exclusive && cmp >= 0:
+		verr = validation.ErrMaxLessThanRequired
+	case !r.min && !r.exclusive && cmp > 0:
+		verr = validation.ErrMaxLessEqualThanRequired
+	default:
+		return nil
+	}
+	return r.thresholdError(verr.SetParams(map[string]any{"threshold": r.threshold}), value)
+}
+
+// thresholdError adds the "min" or "max" and "actual" params to a failed
+// comparison. Type conversion failures from ozzo become [ErrTypeMismatch].
+func (r thresholdRule) thresholdError(err error, value any) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(validation.Error); !ok {
+		return ErrTypeMismatch.SetParams(map[string]any{
+			"type": fmt.Sprintf("%T", value),
+			"want": fmt.Sprintf("%T", r.threshold),
+		})
+	}
+	bound := "max"
+	if r.min {
+		bound = "min"
+	}
+	return withParams(err, map[string]any{bound: r.threshold, "actual": value})
 }
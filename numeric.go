@@ -0,0 +1,116 @@
+package apivalidation
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// decimalString matches the decimal numbers accepted in strings: JSON
+// numbers, plus a leading "+" and a missing integer or fraction part (".7",
+// "1."), as [strconv.ParseFloat] accepts. Fractions and hex forms, which
+// [big.Rat] also parses, are not accepted. Exponents have at most 4 digits:
+// [big.Rat.SetString] expands them, so "1e1000000" would cost a million
+// digits of work.
+var decimalString = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d{1,4})?$`)
+
+// MultipleOf returns a validation rule that checks if a number is a multiple
+// of factor, documented as multipleOf. Values may be ints, floats,
+// [encoding/json.Number] or numeric strings, and are compared exactly: floats are
+// taken as the shortest decimal that represents them, so 0.3 is a multiple
+// of 0.01. On failure it returns [ErrNotMultipleOf] with the "factor" and
+// "actual" params, or [ErrNotDecimal] for strings that are not numbers.
+//
+// MultipleOf panics if factor is not a positive number.
+//
+//	v.Field(&p.Amount, v.MultipleOf(0.05))
+func MultipleOf(factor any) Rule {
+	step, err := toRat(factor)
+	if err != nil || step.Sign() <= 0 {
+		panic(fmt.Sprintf("apivalidation: MultipleOf factor %v is not a positive number", factor))
+	}
+	return &multipleOfRule{factor: factor, step: step}
+}
+
+// Precision returns a validation rule that checks if a number has at most
+// scale decimal places, documented as multipleOf 10^-scale. It accepts the
+// same values as [MultipleOf]; trailing zeros do not count, so "1.50" has a
+// precision of 1. On failure it returns [ErrDecimalMax] with the "max" and
+// "actual" params.
+func Precision(scale uint) Rule {
+	step := new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+	return &multipleOfRule{factor: step.FloatString(int(scale)), step: step, scale: &scale}
+}
+
+type multipleOfRule struct {
+	factor any
+	step   *big.Rat
+	scale  *uint // set by Precision
+}
+
+func (r *multipleOfRule) Validate(value any) error {
+	value, isNil := validation.Indirect(value)
+	if isNil || validation.IsEmpty(value) {
+		return nil
+	}
+	n, err := toRat(value)
+	if err != nil {
+		return err
+	}
+	if new(big.Rat).Quo(n, r.step).IsInt() {
+		return nil
+	}
+	if r.scale != nil {
+		return ErrDecimalMax.SetParams(map[string]any{"max": *r.scale, "actual": value})
+	}
+	return ErrNotMultipleOf.SetParams(map[string]any{"factor": r.factor, "actual": value})
+}
+
+// Describe sets the schema's multipleOf. A second factor on the same field
+// is added as an allOf entry, since a schema has only one.
+func (r *multipleOfRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	f, _ := r.step.Float64()
+	switch {
+	case ref.Value.MultipleOf == nil:
+		ref.Value.MultipleOf = &f
+	case *ref.Value.MultipleOf != f:
+		ref.Value.AllOf = append(ref.Value.AllOf, &openapi3.SchemaRef{Value: &openapi3.Schema{MultipleOf: &f}})
+	}
+	return nil
+}
+
+// toRat converts an int, float, [encoding/json.Number] or numeric string to an exact
+// rational. Floats are converted through their shortest decimal form.
+// Strings that are not numbers return [ErrNotDecimal], and other types
+// [ErrTypeMismatch].
+func toRat(value any) (*big.Rat, error) {
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanInt():
+		return new(big.Rat).SetInt64(rv.Int()), nil
+	case rv.CanUint():
+		return new(big.Rat).SetUint64(rv.Uint()), nil
+	case rv.CanFloat():
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrNotDecimal.SetParams(map[string]any{"actual": value})
+		}
+		r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()))
+		return r, nil
+	case rv.Kind() == reflect.String:
+		s := rv.String()
+		if decimalString.MatchString(s) {
+			if r, ok := new(big.Rat).SetString(s); ok {
+				return r, nil
+			}
+		}
+		return nil, ErrNotDecimal.SetParams(map[string]any{"actual": s})
+	}
+	return nil, ErrTypeMismatch.SetParams(map[string]any{"type": fmt.Sprintf("%T", value), "want": "number"})
+}
@@ -0,0 +1,117 @@
+package apivalidation_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/Gobd/apivalidation/apivalidationtest"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type numericPrice struct {
+	Amount   float64 `json:"amount"`
+	Discount int     `json:"discount"`
+	Step     string  `json:"step"`
+}
+
+func (p *numericPrice) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.Amount, v.Required, v.ExclusiveMin(0.0), v.Precision(2)),
+		v.Field(&p.Discount, v.ExclusiveMax(100), v.MultipleOf(5)),
+		v.Field(&p.Step, v.MultipleOf(0.25)),
+	}
+}
+
+func TestExclusiveMinMax(t *testing.T) {
+	assert.NoError(t, v.ExclusiveMin(0.0).Validate(0.01))
+	assert.NoError(t, v.ExclusiveMin(0).Validate("1"))
+	assert.NoError(t, v.ExclusiveMax(10).Validate(json.Number("9")))
+
+	err := v.ExclusiveMin(1.5).Validate(1.5)
+	require.Error(t, err)
+	verr := err.(validation.Error)
+	assert.Equal(t, validation.ErrMinGreaterThanRequired.Code(), verr.Code())
+	assert.Equal(t, 1.5, verr.Params()["min"])
+	assert.Equal(t, 1.5, verr.Params()["actual"])
+
+	err = v.ExclusiveMax(10).Validate("10")
+	require.Error(t, err)
+	assert.Equal(t, validation.ErrMaxLessThanRequired.Code(), err.(validation.Error).Code())
+}
+
+func TestMultipleOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   v.Rule
+		value  any
+		code   string
+		params map[string]any
+	}{
+		{"int", v.MultipleOf(5), 15, "", nil},
+		{"int fails", v.MultipleOf(5), 12, v.ErrNotMultipleOf.Code(), map[string]any{"factor": 5, "actual": 12}},
+		{"float is exact", v.MultipleOf(0.01), 0.3, "", nil},
+		{"json.Number", v.MultipleOf(0.01), json.Number("19.99"), "", nil},
+		{"string", v.MultipleOf("0.25"), "1.75", "", nil},
+		{"string fails", v.MultipleOf(0.25), "1.1", v.ErrNotMultipleOf.Code(), map[string]any{"factor": 0.25, "actual": "1.1"}},
+		{"not a number", v.MultipleOf(1), "1/2", v.ErrNotDecimal.Code(), map[string]any{"actual": "1/2"}},
+		{"exponent", v.MultipleOf(1), json.Number("1e9999"), "", nil},
+		{"exponent too long", v.MultipleOf(1), json.Number("1e1000000"), v.ErrNotDecimal.Code(), map[string]any{"actual": "1e1000000"}},
+		{"wrong type", v.MultipleOf(1), []int{1}, v.ErrTypeMismatch.Code(), map[string]any{"type": "[]int", "want": "number"}},
+		{"empty", v.MultipleOf(3), "", "", nil},
+		{"precision", v.Precision(2), 10.25, "", nil},
+		{"precision trailing zeros", v.Precision(1), "1.50", "", nil},
+		{"precision fails", v.Precision(2), 0.30000000000000004, v.ErrDecimalMax.Code(), map[string]any{"max": uint(2), "actual": 0.30000000000000004}},
+		{"precision zero", v.Precision(0), json.Number("3.5"), v.ErrDecimalMax.Code(), map[string]any{"max": uint(0), "actual": json.Number("3.5")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate(tt.value)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			verr := err.(validation.Error)
+			assert.Equal(t, tt.code, verr.Code())
+			assert.Equal(t, tt.params, verr.Params())
+		})
+	}
+
+	assert.Panics(t, func() { v.MultipleOf(0) })
+	assert.Panics(t, func() { v.MultipleOf("abc") })
+}
+
+func TestMinMax_ExponentTooLong(t *testing.T) {
+	huge := json.Number("1e1000000")
+	assert.Equal(t, v.ErrNotDecimal.Code(), v.Min("1.5").Validate(huge).(validation.Error).Code())
+	assert.Equal(t, v.ErrNotFloat.Code(), v.Max(1.5).Validate(huge).(validation.Error).Code())
+	assert.NoError(t, v.Min("1.5").Validate(json.Number("1e9999")))
+}
+
+func TestNumeric_Schema(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(numericPrice{})
+	require.NoError(t, err)
+
+	amount := schema.Value.Properties["amount"].Value
+	assert.Equal(t, 0.0, *amount.Min)
+	assert.True(t, amount.ExclusiveMin)
+	assert.Equal(t, 0.01, *amount.MultipleOf)
+
+	discount := schema.Value.Properties["discount"].Value
+	assert.Equal(t, 100.0, *discount.Max)
+	assert.True(t, discount.ExclusiveMax)
+	assert.Equal(t, 5.0, *discount.MultipleOf)
+
+	schema, err = v.NewSchemaRefForValue(numericPrice{}, v.OpenAPI31())
+	require.NoError(t, err)
+	amount = schema.Value.Properties["amount"].Value
+	assert.Nil(t, amount.Min)
+	assert.Equal(t, 0.0, amount.Extensions["exclusiveMinimum"])
+}
+
+func TestNumeric_Parity(t *testing.T) {
+	apivalidationtest.CheckParity(t, numericPrice{Amount: 1, Discount: 5})
+}
@@ -12,4 +12,10 @@
 //	    Response: Order{},
 //	})
 //	http.Handle("/swagger/", openapi.SwaggerHandlerMust("/swagger/", doc))
+//
+// [Handle] registers a typed handler on an [http.ServeMux] and documents it
+// in one call.
+//
+// Named types with rules are emitted once into the document's
+// components.schemas and referenced with $ref (see [ComponentsFor]).
 package openapi
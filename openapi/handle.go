@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	av "github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// HandlerFunc is a typed handler used with [Handle]. Return a nil response
+// for 204 No Content, or an error to answer with [apivalidation.WriteProblem]
+// (return an *[apivalidation.Problem] to control the status).
+type HandlerFunc[Req, Resp any] func(ctx context.Context, req *Req) (*Resp, error)
+
+// Handle registers h on mux under pattern and documents the same operation
+// on doc, so the spec and the runtime behavior cannot drift.
+//
+// pattern is a [http.ServeMux] pattern that must include a method, e.g.
+// "POST /orders" or "GET /orders/{id}". For POST, PUT and PATCH the request
+// body is decoded into Req and validated with
+// [apivalidation.DecodeAndValidateContext]; Req is documented as the request
+// body. Resp is documented as the 200 response, next to the 204 answered for
+// a nil response. If Req has fields tagged with path, query, header or
+// cookie, they are documented as parameters and bound after the body is
+// decoded (with [apivalidation.BindAndValidate], or
+// [apivalidation.BindParams] for PATCH), so a body key cannot override a
+// parameter that is present in the request. PATCH bodies are merge patches
+// decoded onto a zero Req with [apivalidation.DecodeAndValidatePatch] and
+// documented without a required list; the handler gets the keys of the patch
+// from [PatchKeysFrom] to tell absent fields from null or zero ones.
+//
+//	openapi.Handle(mux, doc, "POST /orders", "createOrder",
+//	    func(ctx context.Context, o *Order) (*Order, error) {
+//	        return o, nil
+//	    })
+//
+// Handle panics if pattern has no method or the parameters of Req cannot be
+// documented.
+func Handle[Req, Resp any](mux *http.ServeMux, doc *openapi3.T, pattern, operationID string, h HandlerFunc[Req, Resp]) {
+	method, path := splitPattern(pattern)
+	if method == "" {
+		panic(fmt.Sprintf("openapi: pattern %q has no method", pattern))
+	}
+
+	hasBody := methodHasBody(method)
+	var params openapi3.Parameters
+	if reflect.TypeFor[Req]().Kind() == reflect.Struct {
+		var err error
+		if params, err = av.NewParameters(*new(Req)); err != nil {
+			panic(fmt.Sprintf("openapi: parameters of %s: %v", operationID, err))
+		}
+	}
+	hasParams := len(params) > 0
+	ep := Endpoint{Responses: map[string]Response{
+		"200": {Desc: "OK", Bodies: []any{*new(Resp)}},
+		"204": {Desc: "No Content"},
+	}}
+	if hasBody {
+		ep.Request = *new(Req)
+	}
+	patch := method == http.MethodPatch
+	ep.Partial = patch
+	if hasParams {
+		ep.Params = *new(Req)
+	}
+	addEndpoint(doc, path, method, operationID, ep)
+
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		var err error
+		ctx := r.Context()
+		switch {
+		case patch:
+			keys := &av.PatchKeys{}
+			opts := []av.Option{av.RecordPatchKeys(keys)}
+			if hasParams {
+				opts = append(opts, av.BindParams(r))
+			}
+			err = av.DecodeAndValidatePatch(ctx, r.Body, &req, opts...)
+			ctx = context.WithValue(ctx, patchKeysKey{}, keys)
+		case hasBody && hasParams:
+			// Decode first so that parameters win over body keys for the
+			// same fields.
+			if err = json.NewDecoder(r.Body).Decode(&req); err == nil {
+				err = av.BindAndValidate(r, &req)
+			}
+		case hasBody:
+			err = av.DecodeAndValidateContext(r.Context(), r.Body, &req)
+		case hasParams:
+			err = av.BindAndValidate(r, &req)
+		}
+		if err != nil {
+			av.WriteProblem(w, r, err)
+			return
+		}
+		resp, err := h(ctx, &req)
+		if err != nil {
+			av.WriteProblem(w, r, err)
+			return
+		}
+		if resp == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+type patchKeysKey struct{}
+
+// PatchKeysFrom returns the keys of the merge patch decoded for a PATCH
+// handler registered with [Handle], or nil for other requests.
+//
+//	openapi.Handle(mux, doc, "PATCH /orders/{id}", "patchOrder",
+//	    func(ctx context.Context, p *OrderPatch) (*Order, error) {
+//	        keys := openapi.PatchKeysFrom(ctx)
+//	        if keys.IsNull("/note") {
+//	            // clear the note
+//	        }
+//	        ...
+//	    })
+func PatchKeysFrom(ctx context.Context) *av.PatchKeys {
+	keys, _ := ctx.Value(patchKeysKey{}).(*av.PatchKeys)
+	return keys
+}
+
+// splitPattern splits a [http.ServeMux] pattern into its method and an
+// OpenAPI path. The host is dropped, "{name...}" becomes "{name}" and a
+// trailing "{$}" is removed.
+func splitPattern(pattern string) (method, path string) {
+	pattern = strings.TrimSpace(pattern)
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		method = pattern[:i]
+		pattern = strings.TrimLeft(pattern[i:], " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	pattern = strings.TrimSuffix(pattern, "{$}")
+	pattern = strings.ReplaceAll(pattern, "...}", "}")
+	return method, pattern
+}
+
+func methodHasBody(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
@@ -3,7 +3,10 @@ package openapi
 import (
 	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
+	av "github.com/Gobd/apivalidation"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
@@ -19,9 +22,27 @@ type Endpoint struct {
 	Summary     string
 	Description string
 	Request     any                 // single request body type (convenience)
-	Requests    []any               // multiple request body types (oneOf)
+	Requests    []any               // multiple request body types (oneOf); see [apivalidation.Discriminated] for tagged unions
 	Response    any                 // single 200 response type (convenience)
 	Responses   map[string]Response // full response map (overrides Response if both set)
+	Params      any                 // struct with path/query/header/cookie tags (see [apivalidation.Bind])
+	Strict      bool                // request body schemas disallow additional properties (see [apivalidation.Strict])
+	Partial     bool                // request body schemas have no required list (see [apivalidation.DecodeAndValidatePatch])
+}
+
+// NewParametersMust is like [NewParameters] but panics on error.
+func NewParametersMust(value any) openapi3.Parameters {
+	p, err := NewParameters(value)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewParameters generates OpenAPI parameters from a struct whose fields are
+// tagged with path, query, header or cookie. See [apivalidation.NewParameters].
+func NewParameters(value any) (openapi3.Parameters, error) {
+	return av.NewParameters(value)
 }
 
 // NewRequestMust is like [NewRequest] but panics on error.
@@ -35,6 +56,10 @@ func NewRequestMust(vs ...any) *openapi3.RequestBodyRef {
 
 // NewRequest generates an OpenAPI request body schema from the given value types.
 func NewRequest(vs ...any) (*openapi3.RequestBodyRef, error) {
+	return newRequest(nil, vs...)
+}
+
+func newRequest(opts []av.Option, vs ...any) (*openapi3.RequestBodyRef, error) {
 	if len(vs) == 0 {
 		return nil, errors.New("no values given")
 	}
@@ -55,7 +80,7 @@ func NewRequest(vs ...any) (*openapi3.RequestBodyRef, error) {
 
 	wrapper := base.Value.Content["application/json"].Schema
 	for i := range vs {
-		schema, err := NewSchemaRefForValue(vs[i])
+		schema, err := NewSchemaRefForValue(vs[i], opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -80,8 +105,13 @@ func NewResponseMust(vs map[string]Response) *openapi3.Responses {
 }
 
 // NewResponse creates an OpenAPI responses object.
-// Map key is status code (e.g. "200", "4xx").
+// Map key is status code (e.g. "200", "4xx"). A response without bodies has
+// no content.
 func NewResponse(vs map[string]Response) (*openapi3.Responses, error) {
+	return newResponse(nil, vs)
+}
+
+func newResponse(schemaOpts []av.Option, vs map[string]Response) (*openapi3.Responses, error) {
 	if len(vs) == 0 {
 		return nil, errors.New("no values given")
 	}
@@ -94,25 +124,21 @@ func NewResponse(vs map[string]Response) (*openapi3.Responses, error) {
 		var refs openapi3.SchemaRefs
 
 		for k := range vs[statusCode].Bodies {
-			schema, err := NewSchemaRefForValue(vs[statusCode].Bodies[k])
+			schema, err := NewSchemaRefForValue(vs[statusCode].Bodies[k], schemaOpts...)
 			if err != nil {
 				return nil, err
 			}
 			refs = append(refs, schema)
 		}
 
-		content := openapi3.Content{
-			"application/json": &openapi3.MediaType{
-				Schema: &openapi3.SchemaRef{
-					Value: &openapi3.Schema{
-						OneOf: refs,
-					},
-				},
-			},
-		}
-
-		if len(refs) == 1 {
-			content["application/json"].Schema = refs[0]
+		var content openapi3.Content
+		switch len(refs) {
+		case 0:
+			// No body, e.g. 204 No Content.
+		case 1:
+			content = openapi3.NewContentWithJSONSchemaRef(refs[0])
+		default:
+			content = openapi3.NewContentWithJSONSchema(&openapi3.Schema{OneOf: refs})
 		}
 
 		opt := openapi3.WithName(statusCode, &openapi3.Response{
@@ -138,6 +164,28 @@ func DocBase(serviceName, description, version string) *openapi3.T {
 	}
 }
 
+// DocBase31 is like [DocBase] but returns an OpenAPI 3.1.0 document. The
+// endpoint helpers generate its schemas with [apivalidation.OpenAPI31].
+func DocBase31(serviceName, description, version string) *openapi3.T {
+	doc := DocBase(serviceName, description, version)
+	doc.OpenAPI = "3.1.0"
+	return doc
+}
+
+// is31 reports whether doc is an OpenAPI 3.1 document.
+func is31(doc *openapi3.T) bool {
+	return strings.HasPrefix(doc.OpenAPI, "3.1")
+}
+
+// schemaOptions returns the schema generation options for doc.
+func schemaOptions(doc *openapi3.T) []av.Option {
+	opts := []av.Option{av.WithComponents(ComponentsFor(doc))}
+	if is31(doc) {
+		opts = append(opts, av.OpenAPI31())
+	}
+	return opts
+}
+
 // AddPath adds an operation to the OpenAPI spec at the given path and method.
 func AddPath(path, method string, s *openapi3.T, op *openapi3.Operation) {
 	p := s.Paths.Value(path)
@@ -169,12 +217,33 @@ func addEndpoint(doc *openapi3.T, path, method, operationID string, ep Endpoint)
 		Description: ep.Description,
 	}
 
+	schemaOpts := schemaOptions(doc)
+	if ep.Params != nil {
+		params, err := av.NewParameters(ep.Params, schemaOpts...)
+		if err != nil {
+			panic(err)
+		}
+		op.Parameters = params
+	}
+
 	// Request body
-	switch {
-	case len(ep.Requests) > 0:
-		op.RequestBody = NewRequestMust(ep.Requests...)
-	case ep.Request != nil:
-		op.RequestBody = NewRequestMust(ep.Request)
+	reqOpts := slices.Clone(schemaOpts)
+	if ep.Strict {
+		reqOpts = append(reqOpts, av.Strict())
+	}
+	if ep.Partial {
+		reqOpts = append(reqOpts, av.Partial())
+	}
+	requests := ep.Requests
+	if len(requests) == 0 && ep.Request != nil {
+		requests = []any{ep.Request}
+	}
+	if len(requests) > 0 {
+		body, err := newRequest(reqOpts, requests...)
+		if err != nil {
+			panic(err)
+		}
+		op.RequestBody = body
 	}
 
 	// Responses
@@ -185,10 +254,17 @@ func addEndpoint(doc *openapi3.T, path, method, operationID string, ep Endpoint)
 		}
 	}
 	if responses != nil {
-		op.Responses = NewResponseMust(responses)
+		resps, err := newResponse(schemaOpts, responses)
+		if err != nil {
+			panic(err)
+		}
+		op.Responses = resps
 	} else {
 		op.Responses = openapi3.NewResponses()
 	}
+	if op.RequestBody != nil || len(op.Parameters) > 0 {
+		addProblemResponses(doc, op)
+	}
 
 	AddPath(path, method, doc, op)
 }
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	av "github.com/Gobd/apivalidation"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ProblemSchemaName is the components.schemas key under which the
+// [apivalidation.Problem] schema is registered.
+const ProblemSchemaName = "Problem"
+
+// ProblemResponse returns a response whose application/problem+json body
+// references the shared Problem schema, registering the schema in doc's
+// components if needed. Endpoints with a request body get 400 and 422
+// problem responses automatically.
+func ProblemResponse(doc *openapi3.T, desc string) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(desc).
+			WithContent(openapi3.Content{
+				av.ProblemContentType: &openapi3.MediaType{Schema: problemSchemaRef(doc)},
+			}),
+	}
+}
+
+// problemSchemaRef registers the Problem schema in doc's components and
+// returns a $ref to it.
+func problemSchemaRef(doc *openapi3.T) *openapi3.SchemaRef {
+	if doc.Components == nil {
+		doc.Components = &openapi3.Components{}
+	}
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = openapi3.Schemas{}
+	}
+	existing, ok := doc.Components.Schemas[ProblemSchemaName]
+	if !ok {
+		existing = NewSchemaRefMust(av.Problem{})
+		doc.Components.Schemas[ProblemSchemaName] = existing
+	}
+	return &openapi3.SchemaRef{
+		Ref:   "#/components/schemas/" + ProblemSchemaName,
+		Value: existing.Value,
+	}
+}
+
+// addProblemResponses adds 400 and 422 problem responses to op unless the
+// caller already declared them.
+func addProblemResponses(doc *openapi3.T, op *openapi3.Operation) {
+	if op.Responses.Value("400") == nil {
+		op.Responses.Set("400", ProblemResponse(doc, "Malformed request"))
+	}
+	if op.Responses.Value("422") == nil {
+		op.Responses.Set("422", ProblemResponse(doc, "Validation failed"))
+	}
+}
@@ -1,6 +1,11 @@
 package openapi
 
 import (
+	"reflect"
+	"runtime"
+	"sync"
+	"weak"
+
 	av "github.com/Gobd/apivalidation"
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -8,6 +13,53 @@ import (
 // NewSchemaRefForValue generates an OpenAPI schema for the given value,
 // applying validation rules from types that implement [apivalidation.Ruler],
 // [apivalidation.ContextRuler], or [apivalidation.ValueRuler].
-func NewSchemaRefForValue(value any) (*openapi3.SchemaRef, error) {
-	return av.NewSchemaRefForValue(value)
+// Pass [apivalidation.Strict] to disallow additional properties.
+func NewSchemaRefForValue(value any, opts ...av.Option) (*openapi3.SchemaRef, error) {
+	return av.NewSchemaRefForValue(value, opts...)
+}
+
+// NewSchemaRefMust is like [NewSchemaRefForValue] but panics on error.
+func NewSchemaRefMust(value any) *openapi3.SchemaRef {
+	ref, err := NewSchemaRefForValue(value)
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
+
+// registries holds the component registry of each live document. Entries
+// are removed when their document is garbage collected.
+var registries sync.Map // weak.Pointer[openapi3.T] → *av.Components
+
+// ComponentsFor returns the component registry backed by
+// doc.Components.Schemas, creating the map if needed. The endpoint helpers
+// and [Handle] generate every request and response body through it, so
+// named Ruler and ValueRuler types are emitted once and referenced with
+// $ref. Pass it with [apivalidation.WithComponents] to generate other
+// schemas of doc the same way:
+//
+//	ref, err := openapi.NewSchemaRefForValue(Order{}, av.WithComponents(openapi.ComponentsFor(doc)))
+func ComponentsFor(doc *openapi3.T) *av.Components {
+	if doc.Components == nil {
+		doc.Components = &openapi3.Components{}
+	}
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = openapi3.Schemas{}
+	}
+	key := weak.Make(doc)
+	if c, ok := registries.Load(key); ok {
+		if c := c.(*av.Components); sameSchemas(c.Schemas(), doc.Components.Schemas) {
+			return c
+		}
+	}
+	c := av.NewComponents(doc.Components.Schemas)
+	if _, loaded := registries.Swap(key, c); !loaded {
+		runtime.AddCleanup(doc, func(key weak.Pointer[openapi3.T]) { registries.Delete(key) }, key)
+	}
+	return c
+}
+
+// sameSchemas reports whether a and b are the same map.
+func sameSchemas(a, b openapi3.Schemas) bool {
+	return reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
 }
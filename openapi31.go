@@ -0,0 +1,138 @@
+package apivalidation
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OpenAPI31 generates schemas for OpenAPI 3.1 (JSON Schema 2020-12) instead
+// of 3.0:
+//   - nullable becomes a "null" entry in type
+//   - example becomes examples
+//   - exclusiveMinimum and exclusiveMaximum hold the bound itself
+//   - extensions standing in for JSON Schema keywords in 3.0 are promoted
+//     (x-dependentRequired becomes dependentRequired, x-propertyNames becomes
+//     propertyNames)
+//   - [WhenRule] conditions declared with [WhenRule.IfField] are emitted as
+//     if/then/else instead of prose, and [RequiredIf] as if/then instead of
+//     x-requiredIf, also inside [AllOf] and in the branches of if/then/else
+//
+// kin-openapi models 3.0, so 3.1-only keywords are carried in the schema's
+// Extensions and a 3.1 document does not pass [openapi3.T.Validate].
+func OpenAPI31() Option {
+	return func(o *options) { o.openapi31 = true }
+}
+
+// versionedRule is implemented by rules that document themselves
+// differently in OpenAPI 3.1, and by rules holding other rules. describe31 is
+// called instead of Describe when generating with [OpenAPI31]. schema is nil
+// for rules nested in a subschema, such as the elements of [Each] or the
+// branches of [OneOf], which have no parent object: rules whose 3.1 form is
+// an allOf entry of the parent are described as in 3.0 there.
+type versionedRule interface {
+	describe31(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error
+}
+
+// describeRule calls rule's Describe, or describe31 for OpenAPI 3.1. A nil
+// schema is replaced by a scratch one for Describe.
+func describeRule(rule Rule, o options, name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	if vr, ok := rule.(versionedRule); ok && o.openapi31 {
+		return vr.describe31(name, schema, ref)
+	}
+	if schema == nil {
+		schema = openapi3.NewSchema()
+	}
+	return rule.Describe(name, schema, ref)
+}
+
+// promoted31 maps extensions used for JSON Schema keywords missing from
+// OpenAPI 3.0 to the keyword they become in 3.1.
+var promoted31 = map[string]string{
+	"x-dependentRequired": "dependentRequired",
+	"x-propertyNames":     "propertyNames",
+}
+
+// convert31 rewrites the schemas under root from OpenAPI 3.0 to 3.1 form.
+// Converting a schema twice is a no-op.
+func convert31(root *openapi3.SchemaRef) {
+	seen := map[*openapi3.Schema]bool{}
+	walkSchemaRefs(root, map[*openapi3.SchemaRef]bool{}, func(ref *openapi3.SchemaRef) {
+		if ref.Value == nil || seen[ref.Value] {
+			return
+		}
+		seen[ref.Value] = true
+		convertSchema31(ref.Value)
+	})
+}
+
+func convertSchema31(s *openapi3.Schema) {
+	if s.Example != nil {
+		setExtension(s, "examples", []any{s.Example})
+		s.Example = nil
+	}
+	if s.ExclusiveMin && s.Min != nil {
+		setExtension(s, "exclusiveMinimum", *s.Min)
+		s.Min, s.ExclusiveMin = nil, false
+	}
+	if s.ExclusiveMax && s.Max != nil {
+		setExtension(s, "exclusiveMaximum", *s.Max)
+		s.Max, s.ExclusiveMax = nil, false
+	}
+	for from, to := range promoted31 {
+		if v, ok := s.Extensions[from]; ok {
+			delete(s.Extensions, from)
+			s.Extensions[to] = v
+		}
+	}
+	if !s.Nullable {
+		return
+	}
+	s.Nullable = false
+	if s.Type != nil {
+		if !s.Type.Includes(openapi3.TypeNull) {
+			types := append(slices.Clone(s.Type.Slice()), openapi3.TypeNull)
+			s.Type = (*openapi3.Types)(&types)
+		}
+		return
+	}
+	// Without a type (e.g. an allOf around a $ref), null is an alternative.
+	inner := *s
+	*s = openapi3.Schema{AnyOf: openapi3.SchemaRefs{
+		{Value: &inner},
+		{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeNull}}},
+	}}
+}
+
+func setExtension(s *openapi3.Schema, key string, value any) {
+	if s.Extensions == nil {
+		s.Extensions = map[string]any{}
+	}
+	s.Extensions[key] = value
+}
+
+// ifFieldEquals returns the if schema matching objects whose property name
+// equals value.
+func ifFieldEquals(name string, value any) map[string]any {
+	return map[string]any{
+		"properties": map[string]any{name: map[string]any{"const": value}},
+		"required":   []string{name},
+	}
+}
+
+// conditionalSchema describes rules for the property name into a schema
+// usable as the then or else branch of an if/then/else.
+func conditionalSchema(name string, rules []Rule) (*openapi3.Schema, error) {
+	branch := openapi3.NewSchema()
+	prop := &openapi3.SchemaRef{Value: openapi3.NewSchema()}
+	for _, r := range rules {
+		if err := describeRule(r, options{openapi31: true}, name, branch, prop); err != nil {
+			return nil, err
+		}
+	}
+	if !reflect.ValueOf(*prop.Value).IsZero() {
+		branch.Properties = openapi3.Schemas{name: prop}
+	}
+	return branch, nil
+}
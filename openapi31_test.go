@@ -0,0 +1,28 @@
+package apivalidation
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertSchema31(t *testing.T) {
+	s := &openapi3.Schema{
+		Type:         &openapi3.Types{openapi3.TypeNumber},
+		Min:          openapi3.Ptr(0.0),
+		Max:          openapi3.Ptr(10.0),
+		ExclusiveMin: true,
+		Nullable:     true,
+	}
+	convertSchema31(s)
+	assert.Nil(t, s.Min)
+	assert.False(t, s.ExclusiveMin)
+	assert.Equal(t, 0.0, s.Extensions["exclusiveMinimum"])
+	assert.Equal(t, 10.0, *s.Max, "inclusive bounds are unchanged")
+	assert.Equal(t, []string{"number", "null"}, s.Type.Slice())
+
+	before := *s
+	convertSchema31(s)
+	assert.Equal(t, before, *s, "converting twice is a no-op")
+}
@@ -0,0 +1,69 @@
+package apivalidation
+
+import "net/http"
+
+// Option configures [DecodeAndValidateWith], [UnmarshalAndValidateWith],
+// [DecodeAndValidatePatch] and schema generation ([NewSchemaRefForValue]).
+type Option func(*options)
+
+type options struct {
+	strict    bool
+	maxBytes  int64
+	useNumber bool
+	partial   bool
+	openapi31 bool
+	request   *http.Request
+	patchKeys *PatchKeys
+
+	components *Components
+	// recorder collects components during one schema generation.
+	recorder *componentRecorder
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Strict rejects JSON object keys that do not match a field of the
+// destination type. Each unknown key is reported as [ErrUnknownField] at its
+// JSON path, alongside the validation errors. For schema generation, Strict
+// sets additionalProperties to false on struct schemas.
+func Strict() Option {
+	return func(o *options) { o.strict = true }
+}
+
+// MaxBytes limits the body read by [DecodeAndValidateWith] to n bytes.
+// Larger bodies fail with [ErrBodyTooLarge].
+func MaxBytes(n int64) Option {
+	return func(o *options) { o.maxBytes = n }
+}
+
+// Partial generates schemas without required lists, to document request
+// bodies decoded with [DecodeAndValidatePatch].
+func Partial() Option {
+	return func(o *options) { o.partial = true }
+}
+
+// UseNumber decodes numbers into interface values as [encoding/json.Number]
+// instead of float64, so large integers keep their precision.
+func UseNumber() Option {
+	return func(o *options) { o.useNumber = true }
+}
+
+// BindParams makes [DecodeAndValidatePatch] bind the path, query, header and
+// cookie parameters of r into the destination with [Bind] after applying the
+// patch and before validating. Parameters win over patch keys for the same
+// fields, and those keys are not marked present for partial validation.
+func BindParams(r *http.Request) Option {
+	return func(o *options) { o.request = r }
+}
+
+// RecordPatchKeys makes [DecodeAndValidatePatch] record the keys present in
+// the patch in keys, except those of parameters bound with [BindParams].
+func RecordPatchKeys(keys *PatchKeys) Option {
+	return func(o *options) { o.patchKeys = keys }
+}
@@ -0,0 +1,340 @@
+package apivalidation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// presence records which keys of a JSON document were present. A node with
+// nil children stands for a whole subtree (a scalar, an array, or a path
+// given without descendants), which is validated in full.
+type presence struct {
+	children map[string]*presence
+	// null is set for keys whose value was null.
+	null bool
+}
+
+type presenceKey struct{}
+
+// withPresence returns a context restricting validation to node. A nil node
+// or one standing for a whole subtree validates everything.
+func withPresence(ctx context.Context, node *presence) context.Context {
+	if node != nil && node.children == nil {
+		node = nil
+	}
+	if node == nil && ctx.Value(presenceKey{}) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, presenceKey{}, node)
+}
+
+func presenceFrom(ctx context.Context) *presence {
+	p, _ := ctx.Value(presenceKey{}).(*presence)
+	return p
+}
+
+// childContext returns the context for validating key under the presence
+// node in ctx, or false if key was not present.
+func childContext(ctx context.Context, p *presence, key string) (context.Context, bool) {
+	if p == nil {
+		return ctx, true
+	}
+	child, ok := p.children[key]
+	if !ok {
+		return nil, false
+	}
+	return withPresence(ctx, child), true
+}
+
+func (p *presence) child(key string) *presence {
+	if p.children == nil {
+		p.children = map[string]*presence{}
+	}
+	c, ok := p.children[key]
+	if !ok {
+		c = &presence{}
+		p.children[key] = c
+	}
+	return c
+}
+
+// ValidatePartial is like [ValidateCtx] but only runs the rules of fields at
+// presentPaths and their descendants. Paths are JSON Pointers using the same
+// keys as [FieldErrors] (e.g. "/address/city"); a path without listed
+// descendants validates its whole subtree. Struct-level rules (see [Struct])
+// always run.
+//
+//	err := v.ValidatePartial(ctx, &order, []string{"/customer", "/items"})
+func ValidatePartial(ctx context.Context, dst any, presentPaths []string) error {
+	root := &presence{children: map[string]*presence{}}
+	for _, path := range presentPaths {
+		if path == "" {
+			return ValidateCtx(ctx, dst)
+		}
+		node := root
+		for _, seg := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+			seg = strings.ReplaceAll(seg, "~1", "/")
+			seg = strings.ReplaceAll(seg, "~0", "~")
+			node = node.child(seg)
+		}
+	}
+	return ValidateCtx(context.WithValue(ctx, presenceKey{}, root), dst)
+}
+
+// lookup returns the node at the JSON Pointer path, or the node of the
+// nearest ancestor standing for a whole subtree. It returns nil if the path
+// was not present.
+func (p *presence) lookup(path string) *presence {
+	node := p
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if node.children == nil {
+			return node
+		}
+		seg = strings.ReplaceAll(seg, "~1", "/")
+		seg = strings.ReplaceAll(seg, "~0", "~")
+		child, ok := node.children[seg]
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+	return node
+}
+
+// PatchKeys records the keys of a merge patch applied by
+// [DecodeAndValidatePatch] with [RecordPatchKeys], so code handling the
+// patch can tell a key that was absent from one set to null or to a zero
+// value. Paths are JSON Pointers using the same keys as [FieldErrors]. A nil
+// *PatchKeys has no keys.
+type PatchKeys struct {
+	root *presence
+}
+
+// Has reports whether the patch set the value at path, either directly or
+// by replacing an enclosing value (e.g. "/address/city" after
+// {"address": null}).
+func (k *PatchKeys) Has(path string) bool {
+	return k != nil && k.root != nil && path != "" && k.root.lookup(path) != nil
+}
+
+// IsNull reports whether the patch set the value at path, or an enclosing
+// value, to null.
+func (k *PatchKeys) IsNull(path string) bool {
+	if !k.Has(path) {
+		return false
+	}
+	return k.root.lookup(path).null
+}
+
+// DecodeAndValidatePatch applies the JSON merge patch (RFC 7396) read from r
+// onto dst, then normalizes and validates the result with only the rules of
+// the fields present in the patch (see [ValidatePartial]). dst holds the
+// current value; pass a zero value to validate a patch on its own.
+//
+// Objects are merged key by key into structs and maps, null removes a value
+// (sets the field to its zero value, or deletes the map key), and anything
+// else, including arrays, replaces the current value. [Strict] and
+// [MaxBytes] apply as for [DecodeAndValidateWith]; with [BindParams], request
+// parameters are bound after the patch is applied, and with
+// [RecordPatchKeys], the keys of the patch are recorded.
+func DecodeAndValidatePatch(ctx context.Context, r io.Reader, dst any, opts ...Option) error {
+	o := newOptions(opts)
+	if o.maxBytes > 0 {
+		r = io.LimitReader(r, o.maxBytes+1)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if o.maxBytes > 0 && int64(len(b)) > o.maxBytes {
+		return ErrBodyTooLarge.SetParams(map[string]any{"max": o.maxBytes})
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	var patch json.RawMessage
+	if err := decoder.Decode(&patch); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingData.SetParams(map[string]any{"offset": decoder.InputOffset()})
+	}
+
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(dst)}
+	}
+	root := &presence{}
+	if err := mergePatch(rv.Elem(), patch, root, nil, o); err != nil {
+		return err
+	}
+	if o.request != nil {
+		if err := Bind(o.request, dst); err != nil {
+			return err
+		}
+		for _, pf := range paramFields(rv.Type()) {
+			delete(root.children, pf.errKey)
+		}
+	}
+	if o.patchKeys != nil {
+		o.patchKeys.root = root
+	}
+	return validateDecoded(withPresence(ctx, root), b, dst, o)
+}
+
+// mergePatch merges raw into rv following RFC 7396, recording present keys
+// in node. path is used to report type errors.
+func mergePatch(rv reflect.Value, raw json.RawMessage, node *presence, path []string, o options) error {
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		rv.SetZero()
+		node.null = true
+		return nil
+	}
+	isObject := bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{"))
+	t := rv.Type()
+	custom := reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+
+	switch {
+	case !isObject || custom:
+	case t.Kind() == reflect.Ptr && (t.Elem().Kind() == reflect.Struct || t.Elem().Kind() == reflect.Map):
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return mergePatch(rv.Elem(), raw, node, path, o)
+	case t.Kind() == reflect.Struct:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return err
+		}
+		for k, v := range obj {
+			index, sf, ok := jsonFieldIndex(t, k)
+			if !ok {
+				continue
+			}
+			field, err := rv.FieldByIndexErr(index)
+			if err != nil {
+				// nil embedded pointer: allocate along the path
+				field = fieldByIndexAlloc(rv, index)
+			}
+			name := errorFieldName(sf)
+			if err := mergePatch(field, v, node.child(name), append(path, name), o); err != nil {
+				return err
+			}
+		}
+		if node.children == nil {
+			node.children = map[string]*presence{}
+		}
+		return nil
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return err
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(t))
+		}
+		for k, v := range obj {
+			key := reflect.ValueOf(k).Convert(t.Key())
+			if bytes.Equal(bytes.TrimSpace(v), []byte("null")) {
+				rv.SetMapIndex(key, reflect.Value{})
+				node.child(k).null = true
+				continue
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if cur := rv.MapIndex(key); cur.IsValid() {
+				elem.Set(cur)
+			}
+			if err := mergePatch(elem, v, node.child(k), append(path, k), o); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, elem)
+		}
+		if node.children == nil {
+			node.children = map[string]*presence{}
+		}
+		return nil
+	}
+
+	// Replace the value wholesale.
+	fresh := reflect.New(t)
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if o.useNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(fresh.Interface()); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && len(path) > 0 {
+			typeErr.Field = strings.Join(append(append([]string(nil), path...), fieldPath(typeErr.Field)...), ".")
+		}
+		return err
+	}
+	rv.Set(fresh.Elem())
+	return nil
+}
+
+func fieldPath(field string) []string {
+	if field == "" {
+		return nil
+	}
+	return strings.Split(field, ".")
+}
+
+// jsonFieldIndex finds the field of t that encoding/json would decode key
+// into, matching case-insensitively and following untagged embedded structs.
+func jsonFieldIndex(t reflect.Type, key string) ([]int, reflect.StructField, bool) {
+	var fold []int
+	var foldField reflect.StructField
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" {
+			et := sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				if index, f, ok := jsonFieldIndex(et, key); ok {
+					if fold == nil {
+						fold, foldField = append([]int{i}, index...), f
+					}
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if name == key {
+			return []int{i}, sf, true
+		}
+		if fold == nil && strings.EqualFold(name, key) {
+			fold, foldField = []int{i}, sf
+		}
+	}
+	return fold, foldField, fold != nil
+}
+
+// fieldByIndexAlloc is like FieldByIndex but allocates nil embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,208 @@
+package apivalidation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type patchAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+func (a *patchAddress) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&a.Street, v.Required),
+		v.Field(&a.City, v.Required, v.Length(2, 50)),
+	}
+}
+
+type patchUser struct {
+	Name     string                  `json:"name"`
+	Email    string                  `json:"email"`
+	Nickname *string                 `json:"nickname"`
+	Tags     []string                `json:"tags"`
+	Address  patchAddress            `json:"address"`
+	Labels   map[string]patchAddress `json:"labels"`
+	Internal string                  `json:"-"`
+	Min      int                     `json:"min"`
+	Max      int                     `json:"max"`
+}
+
+func (u *patchUser) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&u.Name, v.Required),
+		v.Field(&u.Email, v.Required),
+		v.Field(&u.Nickname),
+		v.Field(&u.Tags, v.Length(0, 2)),
+		v.Field(&u.Address),
+		v.Field(&u.Labels),
+		v.Field(&u.Min),
+		v.Field(&u.Max, v.GreaterThanField(&u.Min)),
+	}
+}
+
+func patch(t *testing.T, dst *patchUser, body string) error {
+	t.Helper()
+	return v.DecodeAndValidatePatch(context.Background(), strings.NewReader(body), dst)
+}
+
+func TestDecodeAndValidatePatch_OnlyPresentFields(t *testing.T) {
+	var u patchUser
+	require.NoError(t, patch(t, &u, `{"name":"Ada","address":{"city":"Paris"}}`))
+	assert.Equal(t, "Ada", u.Name)
+	assert.Equal(t, "Paris", u.Address.City)
+
+	err := patch(t, &u, `{"address":{"city":"X"},"tags":["a","b","c"]}`)
+	got := map[string]string{}
+	for _, fe := range v.FieldErrors(err) {
+		got[fe.Pointer] = fe.Code
+	}
+	assert.Equal(t, map[string]string{
+		"/address/city": "validation_length_out_of_range",
+		"/tags":         "validation_length_too_long",
+	}, got)
+}
+
+func TestDecodeAndValidatePatch_MergeSemantics(t *testing.T) {
+	nick := "ace"
+	u := patchUser{
+		Name:     "Ada",
+		Email:    "ada@example.com",
+		Nickname: &nick,
+		Tags:     []string{"a", "b"},
+		Address:  patchAddress{Street: "Main", City: "Paris"},
+		Labels:   map[string]patchAddress{"home": {Street: "Main", City: "Paris"}, "work": {Street: "Side", City: "Lyon"}},
+		Internal: "keep",
+	}
+	body := `{"nickname":null,"tags":["c"],"address":{"city":"Rome"},"labels":{"home":{"city":"Oslo"},"work":null}}`
+	require.NoError(t, patch(t, &u, body))
+
+	assert.Equal(t, "Ada", u.Name)
+	assert.Nil(t, u.Nickname)
+	assert.Equal(t, []string{"c"}, u.Tags)
+	assert.Equal(t, patchAddress{Street: "Main", City: "Rome"}, u.Address)
+	assert.Equal(t, map[string]patchAddress{"home": {Street: "Main", City: "Oslo"}}, u.Labels)
+	assert.Equal(t, "keep", u.Internal)
+}
+
+func TestDecodeAndValidatePatch_NullClearsRequired(t *testing.T) {
+	u := patchUser{Name: "Ada", Email: "ada@example.com"}
+	err := patch(t, &u, `{"email":null}`)
+	fes := v.FieldErrors(err)
+	require.Len(t, fes, 1)
+	assert.Equal(t, "/email", fes[0].Pointer)
+	assert.Equal(t, "validation_required", fes[0].Code)
+}
+
+func TestDecodeAndValidatePatch_CrossFieldOnMergedValue(t *testing.T) {
+	u := patchUser{Name: "Ada", Min: 5, Max: 10}
+	err := patch(t, &u, `{"max":3}`)
+	fes := v.FieldErrors(err)
+	require.Len(t, fes, 1)
+	assert.Equal(t, "/max", fes[0].Pointer)
+}
+
+func TestDecodeAndValidatePatch_DecodeErrors(t *testing.T) {
+	var u patchUser
+	err := patch(t, &u, `{"address":{"city":5}}`)
+	p := v.NewProblem(err)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "/address/city", p.Errors[0].Pointer)
+
+	assert.Equal(t, http.StatusBadRequest, v.NewProblem(patch(t, &u, `{"name":"a"} x`)).Status)
+
+	err = v.DecodeAndValidatePatch(context.Background(), strings.NewReader(`{"nmae":"a"}`), &u, v.Strict())
+	fes := v.FieldErrors(err)
+	require.Len(t, fes, 1)
+	assert.Equal(t, "decode_unknown_field", fes[0].Code)
+}
+
+func TestValidatePartial(t *testing.T) {
+	u := patchUser{Address: patchAddress{City: "X"}, Labels: map[string]patchAddress{"a": {}, "b": {Street: "s", City: "cc"}}}
+
+	assert.NoError(t, v.ValidatePartial(context.Background(), &u, nil))
+	assert.NoError(t, v.ValidatePartial(context.Background(), &u, []string{"/labels/b"}))
+
+	fes := v.FieldErrors(v.ValidatePartial(context.Background(), &u, []string{"/name", "/address/city", "/labels/a/street"}))
+	pointers := make([]string, len(fes))
+	for i, fe := range fes {
+		pointers[i] = fe.Pointer
+	}
+	assert.Equal(t, []string{"/address/city", "/labels/a/street", "/name"}, pointers)
+
+	assert.Len(t, v.FieldErrors(v.ValidatePartial(context.Background(), &u, []string{""})), len(v.FieldErrors(v.Validate(&u))))
+}
+
+func TestNewSchemaRefForValue_Partial(t *testing.T) {
+	ref, err := v.NewSchemaRefForValue(patchUser{}, v.Partial())
+	require.NoError(t, err)
+	assert.Empty(t, ref.Value.Required)
+	assert.Empty(t, ref.Value.Properties["address"].Value.Required)
+
+	ref, err = v.NewSchemaRefForValue(patchUser{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, ref.Value.Required)
+}
+
+type patchOrder struct {
+	ID   string `json:"id" path:"id"`
+	Name string `json:"name"`
+}
+
+func (o *patchOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&o.ID, v.Required, v.Length(5, 10)),
+		v.Field(&o.Name, v.Required),
+	}
+}
+
+func TestDecodeAndValidatePatch_RecordPatchKeys(t *testing.T) {
+	u := patchUser{Name: "Ada", Email: "a@b.c", Nickname: new(string), Min: 3}
+	var keys v.PatchKeys
+	body := `{"nickname":null,"min":0,"address":{"city":"Paris"},"labels":{"home":null},"tags":["a"]}`
+	require.NoError(t, v.DecodeAndValidatePatch(context.Background(), strings.NewReader(body), &u, v.RecordPatchKeys(&keys)))
+
+	assert.True(t, keys.IsNull("/nickname"))
+	assert.True(t, keys.Has("/min"))
+	assert.False(t, keys.IsNull("/min"), "0 is a value, not null")
+	assert.False(t, keys.Has("/name"))
+	assert.True(t, keys.Has("/address/city"))
+	assert.False(t, keys.Has("/address/street"))
+	assert.True(t, keys.IsNull("/labels/home"))
+	assert.True(t, keys.Has("/tags/0"), "arrays are replaced as a whole")
+
+	var none *v.PatchKeys
+	assert.False(t, none.Has("/name"))
+}
+
+func TestDecodeAndValidatePatch_BindParams(t *testing.T) {
+	mux := http.NewServeMux()
+	var (
+		o   patchOrder
+		err error
+	)
+	mux.HandleFunc("PATCH /orders/{id}", func(_ http.ResponseWriter, r *http.Request) {
+		o = patchOrder{}
+		err = v.DecodeAndValidatePatch(r.Context(), r.Body, &o, v.BindParams(r))
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/orders/7", strings.NewReader(`{"id":"999999","name":"a"}`)))
+	// The path wins, and "id" is not present, so its rules do not run.
+	require.NoError(t, err)
+	assert.Equal(t, "7", o.ID)
+	assert.Equal(t, "a", o.Name)
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/orders/7", strings.NewReader(`{"name":""}`)))
+	fes := v.FieldErrors(err)
+	require.Len(t, fes, 1)
+	assert.Equal(t, "/name", fes[0].Pointer)
+}
@@ -0,0 +1,135 @@
+package apivalidation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// patterns caches compiled patterns, since Rules() builds rules on every
+// validation.
+var patterns sync.Map // string → *regexp.Regexp
+
+// Pattern returns a validation rule that checks if a string matches the
+// regular expression expr, and documents it as the schema's pattern. Like
+// JSON Schema patterns, expr is not anchored: use ^ and $ to match the whole
+// string. Empty strings pass; combine with [Required] to reject them. On
+// failure it returns ozzo's validation_match_invalid error with the
+// "pattern" param.
+//
+// Pattern panics if expr does not compile or uses syntax outside the
+// subset shared with ECMA-262 regular expressions (see [CompilePattern]).
+// Use it with constant expressions.
+func Pattern(expr string) Rule {
+	r, err := CompilePattern(expr)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// CompilePattern is like [Pattern] but returns an error instead of
+// panicking. Besides compile errors, it rejects Go syntax that clients
+// validating with ECMA-262 regular expressions (as JSON Schema specifies)
+// would reject or interpret differently:
+//   - inline flags such as (?i) and (?s)
+//   - \A, \z, \Q...\E, \C, \p{...} and \P{...}
+//   - \x{...} escapes
+//   - POSIX classes such as [[:alpha:]]
+//   - (?P<name>...) groups; use (?<name>...) instead
+func CompilePattern(expr string) (Rule, error) {
+	if re, ok := patterns.Load(expr); ok {
+		return &patternRule{re: re.(*regexp.Regexp)}, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("apivalidation: pattern %q: %w", expr, err)
+	}
+	if feature := nonECMAFeature(expr); feature != "" {
+		return nil, fmt.Errorf("apivalidation: pattern %q uses %s, which JSON Schema regular expressions do not support", expr, feature)
+	}
+	patterns.Store(expr, re)
+	return &patternRule{re: re}, nil
+}
+
+// nonECMAFeature returns the first Go-only feature used by expr, or "".
+func nonECMAFeature(expr string) string {
+	inClass := false
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\' && i+1 < len(expr):
+			i++
+			switch next := expr[i]; next {
+			case 'A', 'z', 'Q', 'E', 'C', 'p', 'P':
+				return `\` + string(next)
+			case 'x':
+				if i+1 < len(expr) && expr[i+1] == '{' {
+					return `\x{...}`
+				}
+			}
+		case inClass:
+			if c == '[' && i+1 < len(expr) && expr[i+1] == ':' {
+				return "POSIX character classes"
+			}
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			// A leading ] (after an optional ^) is a literal.
+			if i+1 < len(expr) && expr[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(expr) && expr[i+1] == ']' {
+				i++
+			}
+		case c == '(' && strings.HasPrefix(expr[i:], "(?"):
+			rest := expr[i+2:]
+			switch {
+			case strings.HasPrefix(rest, ":"), strings.HasPrefix(rest, "<"):
+			case strings.HasPrefix(rest, "P<"):
+				return "(?P<name>...) groups"
+			default:
+				return "inline flags"
+			}
+		}
+	}
+	return ""
+}
+
+type patternRule struct {
+	re *regexp.Regexp
+}
+
+func (r *patternRule) Validate(value any) error {
+	value, isNil := validation.Indirect(value)
+	if isNil || validation.IsEmpty(value) {
+		return nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return ErrNotString.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+	}
+	if r.re.MatchString(s) {
+		return nil
+	}
+	return validation.ErrMatchInvalid.SetParams(map[string]any{"pattern": r.re.String()})
+}
+
+// Describe sets the schema's pattern. A second pattern on the same field is
+// added as an allOf entry, since a schema has only one.
+func (r *patternRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	expr := r.re.String()
+	switch ref.Value.Pattern {
+	case "":
+		ref.Value.Pattern = expr
+	case expr:
+	default:
+		ref.Value.AllOf = append(ref.Value.AllOf, &openapi3.SchemaRef{Value: &openapi3.Schema{Pattern: expr}})
+	}
+	return nil
+}
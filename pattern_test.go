@@ -0,0 +1,74 @@
+package apivalidation_test
+
+import (
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type patternSKU struct {
+	SKU string `json:"sku"`
+}
+
+func (p *patternSKU) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.SKU, v.Pattern(`^[A-Z]{3}-\d{4}$`), v.Pattern(`^ABC`)),
+	}
+}
+
+func TestPattern_Validate(t *testing.T) {
+	rule := v.Pattern(`^[A-Z]{3}-\d{4}$`)
+
+	assert.NoError(t, rule.Validate("ABC-1234"))
+	assert.NoError(t, rule.Validate(""))
+
+	err := rule.Validate("abc-1234")
+	require.Error(t, err)
+	verr := err.(validation.Error)
+	assert.Equal(t, validation.ErrMatchInvalid.Code(), verr.Code())
+	assert.Equal(t, `^[A-Z]{3}-\d{4}$`, verr.Params()["pattern"])
+
+	assert.Equal(t, v.ErrNotString.Code(), rule.Validate(42).(validation.Error).Code())
+}
+
+func TestPattern_Schema(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(patternSKU{})
+	require.NoError(t, err)
+
+	sku := schema.Value.Properties["sku"].Value
+	assert.Equal(t, `^[A-Z]{3}-\d{4}$`, sku.Pattern)
+	require.Len(t, sku.AllOf, 1)
+	assert.Equal(t, `^ABC`, sku.AllOf[0].Value.Pattern)
+
+	assert.Error(t, v.ValidateJSON(schema, []byte(`{"sku":"XYZ-1234"}`)))
+	assert.NoError(t, v.ValidateJSON(schema, []byte(`{"sku":"ABC-1234"}`)))
+}
+
+func TestCompilePattern(t *testing.T) {
+	for _, expr := range []string{`^a(?:b|c)+$`, `(?<year>\d{4})`, `[\]a-z]`, `[^]a]`, `\bword\b`, `\x41`} {
+		_, err := v.CompilePattern(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	for expr, feature := range map[string]string{
+		`(?i)abc`:     "inline flags",
+		`\Aabc\z`:     `\A`,
+		`abc\z`:       `\z`,
+		`\pL+`:        `\p`,
+		`\x{1F600}`:   `\x{...}`,
+		`[[:alpha:]]`: "POSIX character classes",
+		`(?P<y>\d+)`:  "(?P<name>...) groups",
+		`\Q.*\E`:      `\Q`,
+	} {
+		_, err := v.CompilePattern(expr)
+		assert.ErrorContains(t, err, "uses "+feature, expr)
+	}
+
+	_, err := v.CompilePattern(`a(`)
+	assert.ErrorContains(t, err, "missing closing )")
+
+	assert.Panics(t, func() { v.Pattern(`(?s).`) })
+}
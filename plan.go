@@ -0,0 +1,225 @@
+package apivalidation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// structPlan is the per-type information [Validate] needs to map a
+// FieldRules' field pointer to its struct field. It replaces the linear
+// [findStructField] scan with a lookup by offset from the struct's address.
+// Fields of embedded (non-pointer) structs are included with their offset
+// from the outer struct.
+type structPlan struct {
+	fields map[fieldOffset]*fieldPlan
+}
+
+type fieldOffset struct {
+	offset uintptr
+	typ    reflect.Type
+}
+
+// fieldPlan describes one struct field.
+type fieldPlan struct {
+	field reflect.StructField
+	// name is the error key, named like ozzo-validation does (see [validation.ErrorTag]).
+	name string
+	// bridge reports whether values of the field may need validateCore:
+	// Ruler or ValueRuler types, interfaces, and collections of Rulers.
+	bridge bool
+}
+
+var (
+	structPlans   sync.Map // reflect.Type → *structPlan
+	bridgeTypes   sync.Map // reflect.Type → bool
+	autoValidates sync.Map // reflect.Type → bool
+)
+
+// planFor returns the cached plan for struct type t.
+func planFor(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+	p := &structPlan{fields: map[fieldOffset]*fieldPlan{}}
+	p.add(t, 0)
+	actual, _ := structPlans.LoadOrStore(t, p)
+	return actual.(*structPlan)
+}
+
+func (p *structPlan) add(t reflect.Type, base uintptr) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		p.fields[fieldOffset{base + sf.Offset, sf.Type}] = newFieldPlan(sf)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			p.add(sf.Type, base+sf.Offset)
+		}
+	}
+}
+
+func newFieldPlan(sf reflect.StructField) *fieldPlan {
+	return &fieldPlan{field: sf, name: errorFieldName(sf), bridge: needsBridge(sf.Type)}
+}
+
+// fieldFor returns the plan for the field of structVal that fv points to, or
+// nil if fv does not point into structVal. Fields reached through embedded
+// pointers live outside the struct and fall back to [findStructField].
+func fieldFor(structVal, fv reflect.Value) *fieldPlan {
+	if structVal.CanAddr() {
+		base, ptr := structVal.UnsafeAddr(), fv.Pointer()
+		if ptr >= base && ptr-base < structVal.Type().Size() {
+			if f, ok := planFor(structVal.Type()).fields[fieldOffset{ptr - base, fv.Type().Elem()}]; ok {
+				return f
+			}
+		}
+	}
+	sf := findStructField(structVal, fv)
+	if sf == nil {
+		return nil
+	}
+	return newFieldPlan(*sf)
+}
+
+// errorKeyNamer returns a function resolving pointers to fields of
+// structVal to their error keys, for binding cross-field rules.
+func errorKeyNamer(structVal reflect.Value) func(fieldPtr any) string {
+	return func(fieldPtr any) string {
+		fv := reflect.ValueOf(fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			return ""
+		}
+		if fp := fieldFor(structVal, fv); fp != nil {
+			return fp.name
+		}
+		return ""
+	}
+}
+
+// errorFieldName mirrors ozzo-validation's naming of struct field errors.
+func errorFieldName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get(validation.ErrorTag); tag != "" && tag != "-" {
+		if name := strings.SplitN(tag, ",", 2)[0]; name != "" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// needsBridge reports whether validateCore can do anything with values of t.
+func needsBridge(t reflect.Type) bool {
+	if b, ok := bridgeTypes.Load(t); ok {
+		return b.(bool)
+	}
+	b := computeNeedsBridge(t)
+	bridgeTypes.Store(t, b)
+	return b
+}
+
+func computeNeedsBridge(t reflect.Type) bool {
+	for _, it := range []reflect.Type{t, reflect.PointerTo(t)} {
+		if it.Implements(rulerType) || it.Implements(contextRulerType) || it.Implements(valueRulerType) || it.Implements(unionValueType) {
+			return true
+		}
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr:
+		return needsBridge(t.Elem())
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return shouldAutoValidate(t.Elem())
+	}
+	return false
+}
+
+var (
+	rulerType        = reflect.TypeFor[Ruler]()
+	contextRulerType = reflect.TypeFor[ContextRuler]()
+	valueRulerType   = reflect.TypeFor[ValueRuler]()
+)
+
+// validateStruct validates the struct structPtr points to, like
+// [validation.ValidateStruct] but using the cached [structPlan]. Embedded
+// Ruler fields are expanded for flat error keys, and a rulerBridge is added
+// to fields whose values may hold Ruler children.
+func validateStruct(ctx context.Context, structPtr any, fields []*FieldRules) error {
+	value := reflect.ValueOf(structPtr)
+	if value.Kind() != reflect.Ptr || !value.IsNil() && value.Elem().Kind() != reflect.Struct {
+		return validation.NewInternalError(validation.ErrStructPointer)
+	}
+	if value.IsNil() {
+		return nil
+	}
+	structVal := value.Elem()
+	fields = expandFields(ctx, structPtr, fields)
+	name := errorKeyNamer(structVal)
+
+	errs := validation.Errors{}
+	present := presenceFrom(ctx)
+	hasStructRules := false
+	for i, fr := range fields {
+		if fr.fieldPtr == nil {
+			hasStructRules = true
+			continue
+		}
+		fv := reflect.ValueOf(fr.fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			return validation.NewInternalError(validation.ErrFieldPointer(i))
+		}
+		fp := fieldFor(structVal, fv)
+		if fp == nil {
+			return validation.NewInternalError(validation.ErrFieldNotFound(i))
+		}
+		fieldCtx := ctx
+		if present != nil && !fp.field.Anonymous {
+			var ok bool
+			if fieldCtx, ok = childContext(ctx, present, fp.name); !ok {
+				continue
+			}
+		}
+		for _, r := range fr.rules {
+			bindRule(r, name)
+		}
+
+		n := len(fr.rules)
+		if fp.bridge {
+			n++
+		}
+		rules := make([]validation.Rule, len(fr.rules), n)
+		for j, r := range fr.rules {
+			rules[j] = r
+		}
+		if fp.bridge {
+			rules = append(rules, &rulerBridge{ctx: fieldCtx})
+		}
+
+		err := validation.Validate(fv.Elem().Interface(), rules...)
+		if err == nil {
+			continue
+		}
+		if ie, ok := err.(validation.InternalError); ok && ie.InternalError() != nil {
+			return err
+		}
+		if fp.field.Anonymous {
+			// merge errors from anonymous struct field
+			if es, ok := err.(validation.Errors); ok {
+				for name, value := range es {
+					errs[name] = value
+				}
+				continue
+			}
+		}
+		errs[fp.name] = err
+	}
+	if hasStructRules {
+		applyStructRules(ctx, fields, name, errs)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package apivalidation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ProblemContentType is the media type of [Problem] responses (RFC 9457).
+const ProblemContentType = "application/problem+json"
+
+// Decoding errors reported by [NewProblem]. Like the validation error codes
+// they can be translated through [Messages].
+var (
+	// ErrJSONSyntax is reported for malformed JSON.
+	ErrJSONSyntax = validation.NewError("decode_syntax", "malformed JSON at offset {{.offset}}")
+	// ErrJSONType is reported when a JSON value does not match the Go field type.
+	ErrJSONType = validation.NewError("decode_type", "must be {{.expected}}, got {{.actual}}")
+	// ErrEmptyBody is reported when the request body is empty.
+	ErrEmptyBody = validation.NewError("decode_empty_body", "request body is empty")
+	// ErrUnexpectedEOF is reported when the request body ends mid-value.
+	ErrUnexpectedEOF = validation.NewError("decode_unexpected_eof", "unexpected end of JSON input")
+	// ErrTrailingData is reported when the body has data after the first JSON value.
+	ErrTrailingData = validation.NewError("decode_trailing_data", "unexpected data after JSON value at offset {{.offset}}")
+	// ErrUnknownField is reported by [Strict] decoding for keys that match no field.
+	ErrUnknownField = validation.NewError("decode_unknown_field", "unknown field")
+	// ErrBodyTooLarge is reported when the body exceeds the [MaxBytes] limit.
+	ErrBodyTooLarge = validation.NewError("decode_body_too_large", "request body exceeds {{.max}} bytes")
+)
+
+// Problem is an RFC 9457 problem details object. Errors lists one
+// [FieldError] per offending input, keyed by JSON Pointer.
+//
+// Problem implements error, so handlers can return one to control the
+// response status and body.
+type Problem struct {
+	Type     string       `json:"type,omitempty"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// NewProblem classifies err into a [Problem]:
+//   - decode errors from [DecodeAndValidate] and [UnmarshalAndValidate]
+//     (syntax errors, type mismatches, empty or truncated bodies, trailing
+//     data) → 400
+//   - [ErrBodyTooLarge] → 413
+//   - validation errors → 422, with one entry in Errors per failure
+//   - a *Problem anywhere in the chain → returned as is
+//   - anything else → 500 without details
+func NewProblem(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		internal  validation.InternalError
+		errs      validation.Errors
+		verr      validation.Error
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		return badRequest(ErrJSONSyntax.SetParams(map[string]any{"offset": syntaxErr.Offset}))
+	case errors.As(err, &typeErr):
+		e := ErrJSONType.SetParams(map[string]any{"expected": typeErr.Type.String(), "actual": typeErr.Value})
+		if typeErr.Field == "" {
+			return badRequest(e)
+		}
+		return badRequest(nestError(strings.Split(typeErr.Field, "."), e))
+	case errors.Is(err, io.EOF):
+		return badRequest(ErrEmptyBody)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return badRequest(ErrUnexpectedEOF)
+	case hasCode(err, ErrTrailingData):
+		return badRequest(err)
+	case hasCode(err, ErrBodyTooLarge):
+		return &Problem{
+			Title:  http.StatusText(http.StatusRequestEntityTooLarge),
+			Status: http.StatusRequestEntityTooLarge,
+			Errors: FieldErrors(err),
+		}
+	case errors.As(err, &internal):
+		return &Problem{Title: http.StatusText(http.StatusInternalServerError), Status: http.StatusInternalServerError}
+	case errors.As(err, &errs), errors.As(err, &verr):
+		return &Problem{
+			Title:  "Validation failed",
+			Status: http.StatusUnprocessableEntity,
+			Errors: FieldErrors(err),
+		}
+	}
+	return &Problem{Title: http.StatusText(http.StatusInternalServerError), Status: http.StatusInternalServerError}
+}
+
+// hasCode reports whether err is a [validation.Error] with target's code.
+func hasCode(err error, target validation.Error) bool {
+	var verr validation.Error
+	return errors.As(err, &verr) && verr.Code() == target.Code()
+}
+
+func badRequest(err error) *Problem {
+	return &Problem{
+		Title:  "Malformed request body",
+		Status: http.StatusBadRequest,
+		Errors: FieldErrors(err),
+	}
+}
+
+// nestError wraps err in [ValidationErrors] along path, so it is reported at
+// the matching JSON Pointer.
+func nestError(path []string, err error) error {
+	for i := len(path) - 1; i >= 0; i-- {
+		err = validation.Errors{path[i]: err}
+	}
+	return err
+}
+
+// WriteProblem writes err as an application/problem+json response using
+// [NewProblem]. Messages are translated with the locale from r's context
+// (see [WithLocale]).
+//
+//	if err := v.DecodeAndValidateContext(r.Context(), r.Body, &order); err != nil {
+//	    v.WriteProblem(w, r, err)
+//	    return
+//	}
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	cp := *NewProblem(err)
+	p := &cp
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	locale := ""
+	if r != nil {
+		locale = LocaleFromContext(r.Context())
+		if p.Instance == "" && r.URL != nil {
+			p.Instance = r.URL.Path
+		}
+	}
+	if len(p.Errors) > 0 {
+		fes := make([]FieldError, len(p.Errors))
+		for i, fe := range p.Errors {
+			fe.Message = Messages.Format(locale, fe.Code, fe.Message, fe.Params)
+			fes[i] = fe
+		}
+		p.Errors = fes
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
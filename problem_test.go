@@ -0,0 +1,99 @@
+package apivalidation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type problemOrder struct {
+	ID    string
+	Items []feLine `json:"items"`
+}
+
+func (o *problemOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&o.ID, v.Required),
+		v.Field(&o.Items),
+	}
+}
+
+func decodeProblem(t *testing.T, body string) (*httptest.ResponseRecorder, v.Problem) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	var o problemOrder
+	err := v.DecodeAndValidateContext(r.Context(), r.Body, &o)
+	require.Error(t, err)
+	v.WriteProblem(w, r, err)
+
+	var p v.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	return w, p
+}
+
+func TestWriteProblem_Validation(t *testing.T) {
+	w, p := decodeProblem(t, `{"ID":"1","items":[{"sku":"a"},{"sku":""}]}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, v.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	assert.Equal(t, "/orders", p.Instance)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "/items/1/sku", p.Errors[0].Pointer)
+	assert.Equal(t, "validation_required", p.Errors[0].Code)
+}
+
+func TestWriteProblem_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		code    string
+		pointer string
+	}{
+		{"syntax", `{"ID":}`, "decode_syntax", ""},
+		{"type", `{"items":[{"sku":1}]}`, "decode_type", "/items/0/sku"},
+		{"empty", ``, "decode_empty_body", ""},
+		{"truncated", `{"ID":"1"`, "decode_unexpected_eof", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, p := decodeProblem(t, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			require.Len(t, p.Errors, 1)
+			assert.Equal(t, tt.code, p.Errors[0].Code)
+			assert.Equal(t, tt.pointer, p.Errors[0].Pointer)
+		})
+	}
+}
+
+func TestWriteProblem_Translated(t *testing.T) {
+	v.Messages.Add("zz-test", map[string]string{"decode_empty_body": "leer"})
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r = r.WithContext(v.WithLocale(context.Background(), "zz-test"))
+	w := httptest.NewRecorder()
+	v.WriteProblem(w, r, v.DecodeAndValidate(r.Body, &problemOrder{}))
+
+	var p v.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "leer", p.Errors[0].Message)
+}
+
+func TestNewProblem_Passthrough(t *testing.T) {
+	want := &v.Problem{Title: "Not Found", Status: http.StatusNotFound}
+	assert.Same(t, want, v.NewProblem(want))
+
+	p := v.NewProblem(errors.New("db down"))
+	assert.Equal(t, http.StatusInternalServerError, p.Status)
+	assert.Empty(t, p.Detail)
+}
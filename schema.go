@@ -2,14 +2,18 @@ package apivalidation
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3gen"
 )
 
+var jsonNumberType = reflect.TypeFor[json.Number]()
+
 func indirect(v any) reflect.Value {
 	rv := reflect.ValueOf(v)
 	for rv.Kind() == reflect.Ptr {
@@ -66,6 +70,10 @@ func removeSkippedFields(structVal reflect.Value, schema *openapi3.Schema) {
 // using struct field address comparison.
 func mapFieldsToTags(fields []*FieldRules, structVal reflect.Value) error {
 	for i, fr := range fields {
+		if fr.fieldPtr == nil {
+			// struct-level rule
+			continue
+		}
 		fv := reflect.ValueOf(fr.fieldPtr)
 		if fv.Kind() != reflect.Ptr {
 			return fmt.Errorf("rule target for field index %d must be a pointer, got %s", i, fv.Kind())
@@ -83,15 +91,50 @@ func mapFieldsToTags(fields []*FieldRules, structVal reflect.Value) error {
 	return nil
 }
 
-// applyRulesToSchema calls Describe on each rule for matching schema properties.
-func applyRulesToSchema(fields []*FieldRules, schema *openapi3.Schema) error {
+// jsonNamer returns a function resolving pointers to fields of structVal to
+// their JSON names, for binding cross-field rules.
+func jsonNamer(structVal reflect.Value) func(fieldPtr any) string {
+	return func(fieldPtr any) string {
+		fv := reflect.ValueOf(fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			return ""
+		}
+		sf := findStructField(structVal, fv)
+		if sf == nil {
+			return ""
+		}
+		if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" {
+			return name
+		}
+		return sf.Name
+	}
+}
+
+// applyRulesToSchema calls Describe on each rule for matching schema
+// properties, and on struct-level rules (see [Struct]) with the struct's own
+// schema.
+func applyRulesToSchema(fields []*FieldRules, schema *openapi3.Schema, name func(fieldPtr any) string, o options) error {
+	self := &openapi3.SchemaRef{Value: schema}
+	for _, f := range fields {
+		for _, rule := range f.rules {
+			bindRule(rule, name)
+		}
+		if f.fieldPtr != nil {
+			continue
+		}
+		for _, rule := range f.rules {
+			if err := describeRule(rule, o, "", schema, self); err != nil {
+				return err
+			}
+		}
+	}
 	for k, propRef := range schema.Properties {
 		for _, f := range fields {
 			if f.tag != k {
 				continue
 			}
 			for _, rule := range f.rules {
-				if err := rule.Describe(k, schema, propRef); err != nil {
+				if err := describeRule(rule, o, k, schema, propRef); err != nil {
 					return err
 				}
 			}
@@ -101,26 +144,39 @@ func applyRulesToSchema(fields []*FieldRules, schema *openapi3.Schema) error {
 }
 
 // schemaDoc returns a SchemaCustomizer that applies validation rules to OpenAPI schemas.
-// The value parameter is only used for resolving interface-typed fields to concrete types.
-func schemaDoc(value any) openapi3gen.SchemaCustomizerFn {
+// Interface-typed fields with a registered [Union], and [Discriminated]
+// fields, become a oneOf of the union's variants. With a component recorder
+// in o, described component types are recorded.
+func schemaDoc(o options) openapi3gen.SchemaCustomizerFn {
+	describe := describeSchema(o)
+	if o.recorder == nil {
+		return describe
+	}
+	return func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		if err := describe(name, t, tag, schema); err != nil {
+			return err
+		}
+		o.recorder.record(t, schema)
+		return nil
+	}
+}
+
+func describeSchema(o options) openapi3gen.SchemaCustomizerFn {
 	return func(name string, t reflect.Type, _ reflect.StructTag, schema *openapi3.Schema) error {
-		// Resolve interface-typed fields to their concrete types.
-		if value != nil && indirect(value).Kind() == reflect.Struct {
-			fn := indirect(value).FieldByName(titleFirst(name))
-			if fn.IsValid() && fn.Kind() == reflect.Interface && fn.Elem().IsValid() && fn.Elem().Kind() != reflect.Interface {
-				g := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(schemaDoc(nil)))
-				ref, err := g.NewSchemaRefForValue(fn.Elem().Interface(), nil)
-				if err != nil {
-					return err
-				}
-				*schema = *ref.Value
-				return nil
-			}
+		if u := unionOf(t); u != nil {
+			return u.describe(schema, o)
+		}
+		if t == jsonNumberType {
+			// json.Number is a string type, but marshals as a JSON number.
+			schema.Type = &openapi3.Types{openapi3.TypeNumber}
+		}
+		if o.strict && t.Kind() == reflect.Struct && schema.Type.Is(openapi3.TypeObject) {
+			schema.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.Ptr(false)}
 		}
 
 		vi, fields := getRulesForType(t)
 		if vi == nil {
-			return applyValueRulerSchema(t, name, schema)
+			return applyValueRulerSchema(t, name, schema, o)
 		}
 		structVal := indirect(vi)
 
@@ -133,14 +189,20 @@ func schemaDoc(value any) openapi3gen.SchemaCustomizerFn {
 			return err
 		}
 
-		return applyRulesToSchema(fields, schema)
+		if err := applyRulesToSchema(fields, schema, jsonNamer(structVal), o); err != nil {
+			return err
+		}
+		if o.partial {
+			schema.Required = nil
+		}
+		return nil
 	}
 }
 
 // applyValueRulerSchema checks if a type implements ValueRuler and applies
 // its rules' Describe methods to the schema. Used for non-struct types
 // (e.g. type PaymentMethod string) that carry their own validation rules.
-func applyValueRulerSchema(t reflect.Type, name string, schema *openapi3.Schema) error {
+func applyValueRulerSchema(t reflect.Type, name string, schema *openapi3.Schema, o options) error {
 	inst := reflect.New(t)
 	vr, ok := inst.Interface().(ValueRuler)
 	if !ok {
@@ -148,7 +210,7 @@ func applyValueRulerSchema(t reflect.Type, name string, schema *openapi3.Schema)
 	}
 	ref := &openapi3.SchemaRef{Value: schema}
 	for _, rule := range vr.ValueRules() {
-		if err := rule.Describe(name, schema, ref); err != nil {
+		if err := describeRule(rule, o, name, schema, ref); err != nil {
 			return err
 		}
 	}
@@ -157,8 +219,86 @@ func applyValueRulerSchema(t reflect.Type, name string, schema *openapi3.Schema)
 
 // NewSchemaRefForValue generates an OpenAPI schema for the given value,
 // applying validation rules from types that implement [Ruler],
-// [ContextRuler], or [ValueRuler].
-func NewSchemaRefForValue(value any) (*openapi3.SchemaRef, error) {
-	g := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(schemaDoc(value)))
-	return g.NewSchemaRefForValue(value, nil)
+// [ContextRuler], or [ValueRuler]. With [Strict], struct schemas set
+// additionalProperties to false; with [Partial], they have no required list.
+// With [WithComponents], named Ruler and ValueRuler types are referenced
+// from the registry instead of inlined; with [OpenAPI31], schemas use
+// OpenAPI 3.1 keywords.
+func NewSchemaRefForValue(value any, opts ...Option) (*openapi3.SchemaRef, error) {
+	o := newOptions(opts)
+	if o.components != nil {
+		o.recorder = newComponentRecorder(o.components, o)
+	}
+	g := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(schemaDoc(o)))
+	ref, err := g.NewSchemaRefForValue(value, nil)
+	if err != nil {
+		return nil, err
+	}
+	if o.recorder != nil {
+		o.recorder.replace(ref)
+	}
+	if o.openapi31 {
+		convert31(ref)
+	}
+	return ref, nil
+}
+
+// NewParameters generates OpenAPI parameters for the fields of value tagged
+// with path, query, header or cookie (see [Bind]). Each parameter's schema
+// is generated from the field type and described by the field's rules, so
+// [Required] marks the parameter as required. Path parameters are always
+// required. [OpenAPI31] applies to the parameter schemas.
+func NewParameters(value any, opts ...Option) (openapi3.Parameters, error) {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parameters must be a struct, got %T", value)
+	}
+
+	vi, fields := getRulesForType(t)
+	var structVal reflect.Value
+	if vi != nil {
+		fields = expandFields(context.Background(), vi, fields)
+		structVal = indirect(vi)
+	}
+
+	o := newOptions(opts)
+	g := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(schemaDoc(o)))
+	var params openapi3.Parameters
+	for _, pf := range paramFields(t) {
+		ref, err := g.NewSchemaRefForValue(reflect.Zero(pf.typ).Interface(), nil)
+		if err != nil {
+			return nil, err
+		}
+		// Rules describe the field against its parent; collect that in a
+		// scratch object to learn whether the parameter is required.
+		parent := &openapi3.Schema{Properties: openapi3.Schemas{pf.name: ref}}
+		if vi != nil {
+			addr := structVal.FieldByIndex(pf.index).UnsafeAddr()
+			for _, fr := range fields {
+				fv := reflect.ValueOf(fr.fieldPtr)
+				if fv.Kind() != reflect.Ptr || fv.Pointer() != addr || fv.Elem().Type() != pf.typ {
+					continue
+				}
+				for _, rule := range fr.rules {
+					bindRule(rule, jsonNamer(structVal))
+					if err := describeRule(rule, o, pf.name, parent, ref); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		if o.openapi31 {
+			convert31(ref)
+		}
+		params = append(params, &openapi3.ParameterRef{Value: &openapi3.Parameter{
+			Name:     pf.name,
+			In:       pf.in,
+			Required: pf.in == openapi3.ParameterInPath || slices.Contains(parent.Required, pf.name),
+			Schema:   ref,
+		}})
+	}
+	return params, nil
 }
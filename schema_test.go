@@ -2,8 +2,13 @@ package apivalidation_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	v "github.com/Gobd/apivalidation"
 	"github.com/Gobd/apivalidation/openapi"
@@ -246,13 +251,14 @@ func TestSchema_BasicStruct(t *testing.T) {
 	assert.Equal(t, float64(0), *ageProp.Value.Min)
 	assert.Equal(t, float64(150), *ageProp.Value.Max)
 
-	// Length on name sets min/max
+	// Length on name sets minLength/maxLength
 	nameProp := schema.Properties["name"]
 	require.NotNil(t, nameProp.Value)
-	assert.NotNil(t, nameProp.Value.Min)
-	assert.NotNil(t, nameProp.Value.Max)
-	assert.Equal(t, float64(1), *nameProp.Value.Min)
-	assert.Equal(t, float64(100), *nameProp.Value.Max)
+	assert.Nil(t, nameProp.Value.Min)
+	assert.Nil(t, nameProp.Value.Max)
+	assert.Equal(t, uint64(1), nameProp.Value.MinLength)
+	require.NotNil(t, nameProp.Value.MaxLength)
+	assert.Equal(t, uint64(100), *nameProp.Value.MaxLength)
 }
 
 func TestSchema_Enum(t *testing.T) {
@@ -298,9 +304,8 @@ func TestSchema_DocsSkip(t *testing.T) {
 }
 
 func TestSchema_InterfaceField(t *testing.T) {
-	// When the struct has a concrete value in an interface field,
-	// schemaDoc resolves it. But with a zero-value struct the interface is nil,
-	// so it falls through to the default openapi3gen behavior.
+	// Interface fields without a registered union fall through to the
+	// default openapi3gen behavior.
 	schema := schemaFor(t, schemaWithInterface{})
 	assert.NotNil(t, schema)
 }
@@ -662,3 +667,578 @@ func TestAllMethods(t *testing.T) {
 	assert.NotNil(t, path.Patch)
 	assert.NotNil(t, path.Delete)
 }
+
+func TestPost_ProblemResponses(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+
+	openapi.Post(doc, "/orders", "createOrder", openapi.Endpoint{
+		Request:  schemaBasic{},
+		Response: schemaBasic{},
+	})
+	openapi.Get(doc, "/orders", "listOrders", openapi.Endpoint{
+		Response: schemaBasic{},
+	})
+
+	post := doc.Paths.Value("/orders").Post
+	for _, code := range []string{"400", "422"} {
+		resp := post.Responses.Value(code)
+		require.NotNil(t, resp, code)
+		media := resp.Value.Content.Get(v.ProblemContentType)
+		require.NotNil(t, media)
+		assert.Equal(t, "#/components/schemas/Problem", media.Schema.Ref)
+	}
+	assert.Nil(t, doc.Paths.Value("/orders").Get.Responses.Value("422"))
+	require.Contains(t, doc.Components.Schemas, openapi.ProblemSchemaName)
+	assert.Contains(t, doc.Components.Schemas[openapi.ProblemSchemaName].Value.Properties, "errors")
+
+	require.NoError(t, doc.Validate(context.Background()))
+}
+
+func TestHandle_DocumentsAndServes(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "POST /orders", "createOrder",
+		func(_ context.Context, in *schemaBasic) (*schemaBasic, error) {
+			in.Age++
+			return in, nil
+		})
+	openapi.Handle(mux, doc, "DELETE /orders/{id}", "deleteOrder",
+		func(_ context.Context, _ *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+
+	post := doc.Paths.Value("/orders").Post
+	require.NotNil(t, post)
+	assert.Equal(t, "createOrder", post.OperationID)
+	assert.NotNil(t, post.RequestBody)
+	assert.NotNil(t, post.Responses.Value("422"))
+	assert.NotNil(t, post.Responses.Value("200").Value.Content.Get("application/json"))
+	del := doc.Paths.Value("/orders/{id}").Delete
+	require.NotNil(t, del)
+	assert.Nil(t, del.RequestBody)
+	noContent := del.Responses.Value("204")
+	require.NotNil(t, noContent, "a nil response is answered with 204")
+	assert.Empty(t, noContent.Value.Content)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"name":"a","email":"b","age":1}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"a","email":"b","age":2}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"name":"a"}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, v.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/orders/7", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestHandle_HandlerError(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "GET /orders/{id...}", "getOrder",
+		func(_ context.Context, _ *struct{}) (*schemaBasic, error) {
+			return nil, &v.Problem{Title: "Not Found", Status: http.StatusNotFound}
+		})
+	assert.NotNil(t, doc.Paths.Value("/orders/{id}"))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	assert.Panics(t, func() {
+		openapi.Handle(mux, doc, "/nomethod", "x",
+			func(_ context.Context, _ *struct{}) (*struct{}, error) { return nil, nil })
+	})
+}
+
+type schemaBadParams struct {
+	Limit int `query:"limit"`
+}
+
+func (p *schemaBadParams) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.Limit, v.Min("abc")),
+	}
+}
+
+func TestHandle_BadParamsPanics(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	assert.PanicsWithValue(t, `openapi: parameters of listOrders: threshold "abc" is not a decimal number`, func() {
+		openapi.Handle(mux, doc, "GET /orders", "listOrders",
+			func(_ context.Context, _ *schemaBadParams) (*schemaBasic, error) { return nil, nil })
+	})
+	assert.Nil(t, doc.Paths.Value("/orders"), "nothing is registered")
+}
+
+type schemaOrderParams struct {
+	ID    string `path:"id"`
+	Limit int    `query:"limit"`
+}
+
+func (p *schemaOrderParams) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&p.Limit, v.Required, v.Max(50)),
+	}
+}
+
+func TestEndpoint_Params(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+
+	openapi.Get(doc, "/orders/{id}", "getOrder", openapi.Endpoint{
+		Params:   schemaOrderParams{},
+		Response: schemaBasic{},
+	})
+
+	get := doc.Paths.Value("/orders/{id}").Get
+	require.Len(t, get.Parameters, 2)
+	assert.Equal(t, "id", get.Parameters[0].Value.Name)
+	assert.Equal(t, "path", get.Parameters[0].Value.In)
+	assert.True(t, get.Parameters[1].Value.Required)
+	assert.NotNil(t, get.Responses.Value("422"))
+
+	require.NoError(t, doc.Validate(context.Background()))
+}
+
+func TestHandle_Params(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "GET /orders/{id}", "getOrder",
+		func(_ context.Context, p *schemaOrderParams) (*schemaBasic, error) {
+			return &schemaBasic{Name: p.ID, Age: p.Limit}, nil
+		})
+	require.Len(t, doc.Paths.Value("/orders/{id}").Get.Parameters, 2)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/x?limit=3", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"x","email":"","age":3}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/x?limit=99", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"pointer":"/limit"`)
+}
+
+type schemaOrderUpdate struct {
+	ID   string `json:"id" path:"id"`
+	Name string `json:"name"`
+}
+
+func (u *schemaOrderUpdate) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&u.ID, v.Required),
+		v.Field(&u.Name, v.Required),
+	}
+}
+
+func TestHandle_BodyCannotOverrideParams(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "PUT /orders/{id}", "updateOrder",
+		func(_ context.Context, u *schemaOrderUpdate) (*schemaOrderUpdate, error) {
+			return u, nil
+		})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/orders/7", strings.NewReader(`{"id":"999","name":"a"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":"7","name":"a"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/orders/7", strings.NewReader(`{"id":"999"}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"pointer":"/name"`)
+}
+
+func TestHandle_PatchCannotOverrideParams(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "PATCH /orders/{id}", "patchOrder",
+		func(_ context.Context, u *schemaOrderUpdate) (*schemaOrderUpdate, error) {
+			return u, nil
+		})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/orders/7", strings.NewReader(`{"id":"999","name":"a"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":"7","name":"a"}`, rec.Body.String())
+}
+
+func TestHandle_PatchKeys(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "PATCH /users", "patchUser",
+		func(ctx context.Context, _ *schemaBasic) (*map[string]bool, error) {
+			keys := openapi.PatchKeysFrom(ctx)
+			return &map[string]bool{"has": keys.Has("/age"), "null": keys.IsNull("/age")}, nil
+		})
+
+	for body, want := range map[string]string{
+		`{}`:           `{"has":false,"null":false}`,
+		`{"age":null}`: `{"has":true,"null":true}`,
+		`{"age":0}`:    `{"has":true,"null":false}`,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/users", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, rec.Code, body)
+		assert.JSONEq(t, want, rec.Body.String(), body)
+	}
+	assert.Nil(t, openapi.PatchKeysFrom(context.Background()))
+}
+
+func TestEndpoint_Strict(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+
+	openapi.Post(doc, "/orders", "createOrder", openapi.Endpoint{
+		Request:  schemaBasic{},
+		Response: schemaBasic{},
+		Strict:   true,
+	})
+
+	body := doc.Paths.Value("/orders").Post.RequestBody.Value.Content.Get("application/json").Schema.Value
+	require.NotNil(t, body.AdditionalProperties.Has)
+	assert.False(t, *body.AdditionalProperties.Has)
+
+	resp := doc.Paths.Value("/orders").Post.Responses.Value("200").Value.Content.Get("application/json").Schema.Value
+	assert.Nil(t, resp.AdditionalProperties.Has)
+
+	require.NoError(t, doc.Validate(context.Background()))
+}
+
+func TestEndpoint_Partial(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	mux := http.NewServeMux()
+
+	openapi.Handle(mux, doc, "PATCH /users", "patchUser",
+		func(_ context.Context, in *schemaBasic) (*schemaBasic, error) {
+			return in, nil
+		})
+
+	body := doc.Paths.Value("/users").Patch.RequestBody.Value.Content.Get("application/json").Schema.Value
+	assert.Empty(t, body.Required)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/users", strings.NewReader(`{"age":3}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/users", strings.NewReader(`{"name":""}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+// --- Component schemas ---
+
+type schemaPage[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+}
+
+func (s *schemaPage[T]) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&s.Items, v.Required),
+	}
+}
+
+type schemaReview struct {
+	Score  schemaRating  `json:"score"`
+	Rating *schemaRating `json:"rating"`
+	Child  schemaChild   `json:"child"`
+}
+
+func (s *schemaReview) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&s.Score, v.Describe("overall")),
+		v.Field(&s.Child, v.Required),
+	}
+}
+
+func TestEndpoint_ComponentRefs(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+
+	openapi.Post(doc, "/parents", "createParent", openapi.Endpoint{
+		Request:  schemaParentNested{},
+		Response: schemaParentNested{},
+	})
+	openapi.Get(doc, "/children", "getChild", openapi.Endpoint{
+		Response: schemaChildWithNested{},
+	})
+
+	const prefix = "#/components/schemas/"
+	schemas := doc.Components.Schemas
+	require.Contains(t, schemas, "schemaParentNested")
+	require.Contains(t, schemas, "schemaChildWithNested")
+	require.Contains(t, schemas, "schemaGrandChild")
+
+	post := doc.Paths.Value("/parents").Post
+	assert.Equal(t, prefix+"schemaParentNested", post.RequestBody.Value.Content.Get("application/json").Schema.Ref)
+	assert.Equal(t, prefix+"schemaParentNested", post.Responses.Value("200").Value.Content.Get("application/json").Schema.Ref)
+	get := doc.Paths.Value("/children").Get
+	assert.Equal(t, prefix+"schemaChildWithNested", get.Responses.Value("200").Value.Content.Get("application/json").Schema.Ref)
+
+	children := schemas["schemaParentNested"].Value.Properties["children"].Value
+	assert.Equal(t, prefix+"schemaChildWithNested", children.Items.Ref)
+	nested := schemas["schemaChildWithNested"].Value.Properties["nested"].Value
+	assert.Equal(t, prefix+"schemaGrandChild", nested.Items.Ref)
+	assert.Equal(t, []string{"detail"}, schemas["schemaGrandChild"].Value.Required)
+
+	require.NoError(t, doc.Validate(context.Background()))
+	b, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), `"required":["detail"]`), "schemaGrandChild is emitted once")
+}
+
+func TestEndpoint_ComponentOverlay(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+
+	openapi.Post(doc, "/reviews", "createReview", openapi.Endpoint{
+		Request: schemaReview{},
+	})
+
+	const prefix = "#/components/schemas/"
+	review := doc.Components.Schemas["schemaReview"].Value
+	require.NotNil(t, review)
+
+	// A field rule describing the property keeps the component untouched.
+	score := review.Properties["score"]
+	assert.Empty(t, score.Ref)
+	assert.Equal(t, "overall", score.Value.Description)
+	require.Len(t, score.Value.AllOf, 1)
+	assert.Equal(t, prefix+"schemaRating", score.Value.AllOf[0].Ref)
+	assert.Equal(t, "star rating", doc.Components.Schemas["schemaRating"].Value.Description)
+
+	rating := review.Properties["rating"]
+	assert.True(t, rating.Value.Nullable)
+	require.Len(t, rating.Value.AllOf, 1)
+	assert.Equal(t, prefix+"schemaRating", rating.Value.AllOf[0].Ref)
+
+	// Required only changes the parent, so the property is a plain $ref.
+	assert.Equal(t, prefix+"schemaChild", review.Properties["child"].Ref)
+	assert.Contains(t, review.Required, "child")
+
+	require.NoError(t, doc.Validate(context.Background()))
+}
+
+func TestEndpoint_ComponentNames(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+
+	openapi.Get(doc, "/basics", "listBasics", openapi.Endpoint{
+		Response: schemaPage[schemaBasic]{},
+	})
+	openapi.Get(doc, "/children", "listChildren", openapi.Endpoint{
+		Response: schemaPage[*schemaChild]{},
+	})
+	openapi.Patch(doc, "/basics", "patchBasic", openapi.Endpoint{
+		Request: schemaBasic{},
+		Partial: true,
+	})
+
+	schemas := doc.Components.Schemas
+	assert.Contains(t, schemas, "schemaPage_schemaBasic")
+	assert.Contains(t, schemas, "schemaPage_schemaChild")
+	assert.Contains(t, schemas, "schemaBasic")
+	require.Contains(t, schemas, "schemaBasicPartial")
+	assert.Empty(t, schemas["schemaBasicPartial"].Value.Required)
+	assert.NotEmpty(t, schemas["schemaBasic"].Value.Required)
+
+	require.NoError(t, doc.Validate(context.Background()))
+}
+
+func TestComponentsFor_ReleasesDocument(t *testing.T) {
+	doc := openapi.DocBase("test", "test", "1.0")
+	c := openapi.ComponentsFor(doc)
+	assert.Same(t, c, openapi.ComponentsFor(doc))
+
+	collected := make(chan struct{})
+	runtime.AddCleanup(doc, func(ch chan struct{}) { close(ch) }, collected)
+	doc = nil
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		select {
+		case <-collected:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond, "the registry must not keep its document alive")
+}
+
+func TestNewSchemaRefForValue_WithComponents(t *testing.T) {
+	schemas := openapi3.Schemas{}
+	components := v.NewComponents(schemas)
+
+	ref, err := v.NewSchemaRefForValue(schemaWithValueRuler{}, v.WithComponents(components))
+	require.NoError(t, err)
+	assert.Equal(t, "#/components/schemas/schemaWithValueRuler", ref.Ref)
+	require.NotNil(t, ref.Value)
+
+	method := schemas["schemaWithValueRuler"].Value.Properties["method"]
+	assert.Equal(t, "#/components/schemas/schemaPaymentMethod", method.Ref)
+	assert.Len(t, schemas["schemaPaymentMethod"].Value.Enum, 2)
+
+	// Without the option, schemas stay inline.
+	ref, err = v.NewSchemaRefForValue(schemaWithValueRuler{})
+	require.NoError(t, err)
+	assert.Empty(t, ref.Ref)
+	assert.Len(t, ref.Value.Properties["method"].Value.Enum, 2)
+}
+
+// --- OpenAPI 3.1 ---
+
+type schema31 struct {
+	Nick   *string       `json:"nick"`
+	Rating *schemaRating `json:"rating"`
+	Age    int           `json:"age"`
+	Method string        `json:"method"`
+	Card   string        `json:"card"`
+	Street string        `json:"street"`
+	Zip    string        `json:"zip"`
+}
+
+func (s *schema31) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&s.Age, v.Example(30), v.Min(1)),
+		v.Field(&s.Card, v.When(s.Method == "card", "method is card", v.Required, v.Length(12, 19)).
+			IfField(&s.Method, "card").
+			Else(v.Empty)),
+		v.Field(&s.Zip, v.RequiredWith(&s.Street)),
+	}
+}
+
+type whenMethod string
+
+type whenOrder struct {
+	Method whenMethod `json:"method"`
+	Card   string     `json:"card"`
+}
+
+func (o *whenOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		// The condition given to When is replaced by IfField.
+		v.Field(&o.Card, v.When(false, "method is card", v.Required).IfField(&o.Method, "card").Else(v.Empty)),
+	}
+}
+
+func TestWhen_IfFieldValidates(t *testing.T) {
+	assert.NoError(t, v.Validate(&whenOrder{Method: "card", Card: "4242"}))
+	assert.NoError(t, v.Validate(&whenOrder{Method: "ach"}))
+
+	errs := v.FieldErrors(v.Validate(&whenOrder{Method: "card"}))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/card", errs[0].Pointer)
+	assert.Equal(t, "validation_required", errs[0].Code)
+
+	errs = v.FieldErrors(v.Validate(&whenOrder{Method: "ach", Card: "4242"}))
+	require.Len(t, errs, 1, "the else rules apply when the field does not match")
+	assert.Equal(t, "/card", errs[0].Pointer)
+
+	var o whenOrder
+	assert.Panics(t, func() { v.When(true, "").IfField(&o.Method, 3) })
+	assert.Panics(t, func() { v.When(true, "").IfField(o.Method, "card") })
+}
+
+func TestSchema_OpenAPI31(t *testing.T) {
+	ref, err := v.NewSchemaRefForValue(schema31{}, v.OpenAPI31())
+	require.NoError(t, err)
+	schema := ref.Value
+
+	nick := schema.Properties["nick"].Value
+	assert.False(t, nick.Nullable)
+	assert.Equal(t, []string{"string", "null"}, nick.Type.Slice())
+
+	age := schema.Properties["age"].Value
+	assert.Nil(t, age.Example)
+	assert.Equal(t, []any{30}, age.Extensions["examples"])
+
+	assert.Contains(t, schema.Extensions, "dependentRequired")
+	assert.NotContains(t, schema.Extensions, "x-dependentRequired")
+
+	card := schema.Properties["card"].Value
+	assert.NotContains(t, card.Description, "method is card")
+	require.Len(t, schema.AllOf, 1)
+	cond := schema.AllOf[0].Value.Extensions
+	assert.Equal(t, map[string]any{
+		"properties": map[string]any{"method": map[string]any{"const": "card"}},
+		"required":   []string{"method"},
+	}, cond["if"])
+	then := cond["then"].(*openapi3.Schema)
+	assert.Equal(t, []string{"card"}, then.Required)
+	assert.Equal(t, uint64(12), then.Properties["card"].Value.MinLength)
+	assert.NotNil(t, cond["else"])
+
+	b, err := json.Marshal(ref)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"type":["string","null"]`)
+	assert.Contains(t, string(b), `"then":{`)
+	assert.NotContains(t, string(b), `"nullable"`)
+
+	// OpenAPI 3.0 keeps nullable, example and prose.
+	schema = schemaFor(t, schema31{})
+	assert.True(t, schema.Properties["nick"].Value.Nullable)
+	assert.Equal(t, 30, schema.Properties["age"].Value.Example)
+	assert.Contains(t, schema.Properties["card"].Value.Description, "method is card")
+	assert.Empty(t, schema.AllOf)
+}
+
+type nested31 struct {
+	Method string   `json:"method"`
+	Card   string   `json:"card"`
+	Notes  []string `json:"notes"`
+}
+
+func (n *nested31) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&n.Card, v.AllOf(v.When(true, "method is card", v.Length(12, 19)).IfField(&n.Method, "card"))),
+		v.Field(&n.Notes, v.Each(v.OneOf(v.Length(1, 5), v.RequiredIf(&n.Method, "note")))),
+	}
+}
+
+func TestSchema_OpenAPI31Nested(t *testing.T) {
+	ref, err := v.NewSchemaRefForValue(nested31{}, v.OpenAPI31())
+	require.NoError(t, err)
+	schema := ref.Value
+
+	// Rules in AllOf document the parent like rules given to Field.
+	require.Len(t, schema.AllOf, 1)
+	then := schema.AllOf[0].Value.Extensions["then"].(*openapi3.Schema)
+	assert.Equal(t, uint64(12), then.Properties["card"].Value.MinLength)
+	assert.Empty(t, schema.Properties["card"].Value.Description)
+
+	// Element subschemas have no parent, so RequiredIf keeps its prose.
+	branches := schema.Properties["notes"].Value.Items.Value.OneOf
+	require.Len(t, branches, 2)
+	assert.Contains(t, branches[1].Value.Description, "method")
+}
+
+func TestDocBase31(t *testing.T) {
+	doc := openapi.DocBase31("test", "test", "1.0")
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+
+	openapi.Post(doc, "/reviews", "createReview", openapi.Endpoint{
+		Request:  schemaReview{},
+		Response: schema31{},
+	})
+
+	rating := doc.Components.Schemas["schemaReview"].Value.Properties["rating"].Value
+	require.Len(t, rating.AnyOf, 2)
+	assert.Equal(t, "#/components/schemas/schemaRating", rating.AnyOf[0].Value.AllOf[0].Ref)
+	assert.Equal(t, []string{"null"}, rating.AnyOf[1].Value.Type.Slice())
+
+	resp := doc.Paths.Value("/reviews").Post.Responses.Value("200").Value.Content.Get("application/json").Schema.Value
+	assert.Equal(t, []string{"string", "null"}, resp.Properties["nick"].Value.Type.Slice())
+
+	_, err := openapi.SwaggerHandler("/swagger/", doc)
+	require.NoError(t, err)
+}
@@ -22,28 +22,42 @@ func NewStringRuleWithError(validator func(string) bool, err validation.Error, d
 }
 
 // NewStringRule returns a string validation rule using desc as both the error message and schema description.
+// The error carries the [ErrStringInvalid] code.
 func NewStringRule(validator func(string) bool, desc string) Rule {
 	return stringRule{
-		validation.NewStringRule(validator, desc),
+		validation.NewStringRuleWithError(validator, ErrStringInvalid.SetMessage(desc)),
 		desc,
 	}
 }
 
 // NewStringRuleDecimalMax returns a validation rule that limits the number of decimal places in a numeric string.
+// It only adds prose to the schema; [Precision] also accepts numbers and is documented as multipleOf.
 func NewStringRuleDecimalMax(i uint) Rule {
 	desc := fmt.Sprintf("no more than %d decimals", i)
 	return stringRule{
-		validation.NewStringRule(func(s string) bool {
+		validation.NewStringRuleWithError(func(s string) bool {
 			spl := strings.Split(s, ".")
 			if len(spl) < 2 {
 				return true
 			}
 			return len(spl[1]) <= int(i)
-		}, desc),
+		}, ErrDecimalMax.SetParams(map[string]any{"max": i})),
 		desc,
 	}
 }
 
+// Validate implements [Rule], returning [ErrNotString] for non-string values.
+func (r stringRule) Validate(value any) error {
+	err := r.StringRule.Validate(value)
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(validation.Error); !ok {
+		return ErrNotString.SetParams(map[string]any{"type": fmt.Sprintf("%T", value)})
+	}
+	return err
+}
+
 func (r stringRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
 	if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
 		ref.Value.Description += " "
@@ -26,7 +26,7 @@ func expandFields(ctx context.Context, structPtr any, fields []*FieldRules) []*F
 	for _, fr := range fields {
 		fv := reflect.ValueOf(fr.fieldPtr)
 		if fv.Kind() == reflect.Ptr {
-			if sf := findStructField(structVal, fv); sf != nil && sf.Anonymous {
+			if fp := fieldFor(structVal, fv); fp != nil && fp.field.Anonymous {
 				embeddedPtr := fv.Interface()
 				if r, ok := embeddedPtr.(Ruler); ok {
 					result = append(result, expandFields(ctx, embeddedPtr, r.Rules())...)
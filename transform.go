@@ -53,6 +53,11 @@ func callNormalize(ctx context.Context, v any) {
 func walkNormalize(ctx context.Context, rv reflect.Value) { //nolint:revive // reflection walker is inherently complex
 	for i := range rv.NumField() {
 		field := rv.Field(i)
+		if !field.CanInterface() {
+			// Unexported fields (including unexported embedded structs) cannot be
+			// passed to Normalize without panicking.
+			continue
+		}
 		switch field.Kind() {
 		case reflect.Struct:
 			if field.CanAddr() {
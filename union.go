@@ -0,0 +1,336 @@
+package apivalidation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// unions holds the registered unions by interface type.
+var unions sync.Map // reflect.Type → *union
+
+// Union describes the JSON encoding of interface type T: objects whose
+// discriminator property names the concrete type. Register a union once,
+// during initialization, and use [Discriminated] fields to decode it:
+//
+//	var Payments = v.NewUnion[Payment]("type").
+//	    Variant("card", CardPayment{}).
+//	    Variant("ach", ACHPayment{})
+//
+// Schemas of Discriminated[T] fields, and of fields of type T, are a oneOf
+// of the variants with a discriminator. Each variant schema requires the
+// discriminator property, with an enum of the variant's values. With
+// [WithComponents], every named variant is registered as a component, so
+// the discriminator also gets a mapping.
+type Union[T any] struct {
+	u *union
+}
+
+type union struct {
+	property string
+	values   []string
+	variants map[string]reflect.Type
+}
+
+// NewUnion registers a union for interface type T, replacing any previous
+// one, with the given discriminator property. It panics if T is not an
+// interface type.
+func NewUnion[T any](property string) *Union[T] {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("apivalidation: NewUnion: %s is not an interface type", t))
+	}
+	u := &union{property: property, variants: map[string]reflect.Type{}}
+	unions.Store(t, u)
+	return &Union[T]{u: u}
+}
+
+// Variant registers the concrete type of sample for discriminator value.
+// Pass a pointer (e.g. &CardPayment{}) if only the pointer implements T.
+func (u *Union[T]) Variant(value string, sample T) *Union[T] {
+	t := reflect.TypeOf(sample)
+	if t == nil {
+		panic("apivalidation: Union.Variant: nil sample")
+	}
+	if _, ok := u.u.variants[value]; !ok {
+		u.u.values = append(u.u.values, value)
+	}
+	u.u.variants[value] = t
+	return u
+}
+
+// Unmarshal decodes b into the variant named by its discriminator. It
+// returns a nil T if the discriminator is missing or unknown.
+func (u *Union[T]) Unmarshal(b []byte) (T, error) {
+	var zero T
+	v, _, err := u.u.unmarshal(b, reflect.TypeFor[T]())
+	if err != nil || v == nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// unionFor returns the union registered for interface type t, if any.
+func unionFor(t reflect.Type) *union {
+	if u, ok := unions.Load(t); ok {
+		return u.(*union)
+	}
+	return nil
+}
+
+// discriminator returns the discriminator value of the JSON object b.
+func (u *union) discriminator(b []byte, iface reflect.Type) (string, error) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return "", &json.UnmarshalTypeError{Value: jsonKind(trimmed), Type: iface}
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return "", err
+	}
+	raw, ok := probe[u.property]
+	if !ok {
+		return "", nil
+	}
+	var tag string
+	if err := json.Unmarshal(raw, &tag); err != nil {
+		return "", &json.UnmarshalTypeError{Value: jsonKind(bytes.TrimSpace(raw)), Type: reflect.TypeFor[string](), Field: u.property}
+	}
+	return tag, nil
+}
+
+// unmarshal decodes b into the variant named by its discriminator tag. v is
+// nil if there is no variant for tag.
+func (u *union) unmarshal(b []byte, iface reflect.Type) (v any, tag string, err error) {
+	if tag, err = u.discriminator(b, iface); err != nil {
+		return nil, "", err
+	}
+	t, ok := u.variants[tag]
+	if !ok {
+		return nil, tag, nil
+	}
+	et := t
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	ptr := reflect.New(et)
+	if err := json.Unmarshal(b, ptr.Interface()); err != nil {
+		return nil, tag, err
+	}
+	if t.Kind() == reflect.Ptr {
+		return ptr.Interface(), tag, nil
+	}
+	return ptr.Elem().Interface(), tag, nil
+}
+
+// variantError returns the error for a decoded object whose discriminator
+// is missing or unknown.
+func (u *union) variantError(tag string) error {
+	if tag == "" {
+		return validation.Errors{u.property: validation.ErrRequired}
+	}
+	allowed := make([]any, len(u.values))
+	for i, v := range u.values {
+		allowed[i] = v
+	}
+	return validation.Errors{u.property: ErrInInvalid.SetParams(map[string]any{"allowed": allowed, "actual": tag})}
+}
+
+// describe sets schema to a oneOf of the variant schemas.
+func (u *union) describe(schema *openapi3.Schema, o options) error {
+	schema.Type = nil
+	schema.Properties = nil
+	schema.OneOf = nil
+	discriminator := &openapi3.Discriminator{PropertyName: u.property}
+	seen := map[reflect.Type]*openapi3.SchemaRef{}
+	for _, value := range u.values {
+		t := u.variants[value]
+		ref, ok := seen[t]
+		if !ok {
+			g := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(u.variantDoc(t, o)))
+			var err error
+			if ref, err = g.NewSchemaRefForValue(reflect.Zero(t).Interface(), nil); err != nil {
+				return err
+			}
+			seen[t] = ref
+			schema.OneOf = append(schema.OneOf, ref)
+		}
+		if o.recorder == nil {
+			continue
+		}
+		if rc, ok := o.recorder.nodes[ref.Value]; ok {
+			if discriminator.Mapping == nil {
+				discriminator.Mapping = openapi3.StringMap{}
+			}
+			discriminator.Mapping[value] = rc.ref.Ref
+		}
+	}
+	schema.Discriminator = discriminator
+	return nil
+}
+
+// variantDoc is like schemaDoc, but also documents the discriminator
+// property on the schema of variant type t, and records that schema as a
+// component even if t has no rules.
+func (u *union) variantDoc(t reflect.Type, o options) openapi3gen.SchemaCustomizerFn {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	describe := describeSchema(o)
+	return func(name string, st reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		if err := describe(name, st, tag, schema); err != nil {
+			return err
+		}
+		if st != t {
+			if o.recorder != nil {
+				o.recorder.record(st, schema)
+			}
+			return nil
+		}
+		u.describeVariant(t, schema)
+		if o.recorder != nil {
+			o.recorder.add(st, schema)
+		}
+		return nil
+	}
+}
+
+// describeVariant makes schema require the discriminator property, with an
+// enum of the values registered for variant type t.
+func (u *union) describeVariant(t reflect.Type, schema *openapi3.Schema) {
+	var values []any
+	for _, value := range u.values {
+		vt := u.variants[value]
+		if vt.Kind() == reflect.Ptr {
+			vt = vt.Elem()
+		}
+		if vt == t {
+			values = append(values, value)
+		}
+	}
+	prop := schema.Properties[u.property]
+	if prop == nil || prop.Value == nil {
+		prop = openapi3.NewStringSchema().NewRef()
+		if schema.Properties == nil {
+			schema.Properties = openapi3.Schemas{}
+		}
+		schema.Properties[u.property] = prop
+	}
+	prop.Value.Enum = values
+	if !slices.Contains(schema.Required, u.property) {
+		schema.Required = append(schema.Required, u.property)
+	}
+}
+
+// jsonKind names the kind of the JSON value b, for type errors.
+func jsonKind(b []byte) string {
+	if len(b) == 0 {
+		return "nothing"
+	}
+	switch b[0] {
+	case '"':
+		return "string"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	case '{':
+		return "object"
+	}
+	return "number"
+}
+
+// unionValue is implemented by [Discriminated] to validate the decoded
+// variant.
+type unionValue interface {
+	unionType() reflect.Type
+	validateUnion(ctx context.Context) error
+}
+
+var unionValueType = reflect.TypeFor[unionValue]()
+
+// Discriminated holds a value of interface type T decoded through the
+// [Union] registered for T. It marshals as its Value.
+//
+// Validation runs the rules of the decoded variant, with errors keyed as if
+// the variant were the field itself. A missing or unknown discriminator is
+// reported at the discriminator property. Use a pointer field
+// (*Discriminated[T]) to make the value optional or [Required].
+//
+//	type Checkout struct {
+//	    Payment v.Discriminated[Payment] `json:"payment"`
+//	}
+type Discriminated[T any] struct {
+	Value T
+
+	tag     string // discriminator value read by UnmarshalJSON
+	decoded bool
+}
+
+// UnmarshalJSON decodes b into the variant named by its discriminator.
+func (d *Discriminated[T]) UnmarshalJSON(b []byte) error {
+	var zero T
+	d.Value, d.tag, d.decoded = zero, "", false
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil
+	}
+	t := reflect.TypeFor[T]()
+	u := unionFor(t)
+	if u == nil {
+		return fmt.Errorf("apivalidation: no union registered for %s", t)
+	}
+	v, tag, err := u.unmarshal(b, t)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		d.Value = v.(T)
+	}
+	d.tag, d.decoded = tag, true
+	return nil
+}
+
+// MarshalJSON encodes Value.
+func (d Discriminated[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Value)
+}
+
+func (d Discriminated[T]) unionType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (d Discriminated[T]) validateUnion(ctx context.Context) error {
+	u := unionFor(d.unionType())
+	if u == nil {
+		return nil
+	}
+	if !reflect.ValueOf(&d.Value).Elem().IsNil() {
+		return validateCore(ctx, d.Value)
+	}
+	if !d.decoded {
+		return nil
+	}
+	return u.variantError(d.tag)
+}
+
+// unionOf returns the union described by t: a registered interface type or
+// a [Discriminated] type.
+func unionOf(t reflect.Type) *union {
+	if t.Kind() == reflect.Interface {
+		return unionFor(t)
+	}
+	if t.Implements(unionValueType) {
+		return unionFor(reflect.Zero(t).Interface().(unionValue).unionType())
+	}
+	return nil
+}
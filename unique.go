@@ -1,10 +1,14 @@
 package apivalidation
 
 import (
-	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type uniqueRule struct {
@@ -18,6 +22,7 @@ func (r uniqueRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaR
 }
 
 // Unique returns a validation rule that checks if all elements in a slice are unique according to f.
+// On failure it returns [ErrNotUnique].
 func Unique(f func(a int) any, desc string) Rule {
 	return uniqueRule{
 		desc: desc,
@@ -42,10 +47,193 @@ func (r uniqueRule) Validate(value any) error {
 			m[r.f(i)] = struct{}{}
 		}
 		if len(m) != l {
-			return errors.New("not unique")
+			return ErrNotUnique.SetParams(map[string]any{"desc": r.desc})
 		}
 	default:
-		return errors.New("must be slice")
+		return ErrNotSlice.SetParams(map[string]any{"type": rv.Kind().String()})
 	}
 	return nil
 }
+
+// UniqueItems returns a validation rule that checks that no two elements of
+// a slice or array are equal, documented as uniqueItems. Pointer elements are
+// compared by the values they point to. On failure it returns
+// [validation.Errors] keyed by the index of each duplicate, holding
+// [ErrDuplicateItem] with the "index" of the element and of the "first"
+// element it duplicates.
+func UniqueItems() Rule {
+	return &uniqueItemsRule{}
+}
+
+type uniqueItemsRule struct{}
+
+func (*uniqueItemsRule) Validate(value any) error {
+	return validateUnique(value, nil, func(elem reflect.Value) any {
+		for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+			if elem.IsNil() {
+				return nil
+			}
+			elem = elem.Elem()
+		}
+		return elem.Interface()
+	})
+}
+
+func (*uniqueItemsRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	ref.Value.UniqueItems = true
+	return nil
+}
+
+// UniqueBy returns a validation rule that checks that key returns a
+// different value for each element of a []T or [N]T. Errors are the same as
+// for [UniqueItems]. key is arbitrary code, so the rule is not documented in
+// the schema; use [UniqueByFields] to document the fields of the key.
+//
+//	v.Field(&c.Items, v.UniqueBy(func(it LineItem) string { return strings.ToLower(it.SKU) }))
+func UniqueBy[T any, K comparable](key func(T) K) Rule {
+	return &uniqueByRule[T, K]{key: key}
+}
+
+type uniqueByRule[T any, K comparable] struct {
+	key func(T) K
+}
+
+func (r *uniqueByRule[T, K]) Validate(value any) error {
+	return validateUnique(value, reflect.TypeFor[T](), func(elem reflect.Value) any {
+		return r.key(elem.Interface().(T))
+	})
+}
+
+func (r *uniqueByRule[T, K]) Describe(_ string, _ *openapi3.Schema, _ *openapi3.SchemaRef) error {
+	return nil
+}
+
+// UniqueByFields returns a validation rule that checks that no two elements
+// of a []T or [N]T have equal values for all of the struct fields with the
+// given JSON names, documented as x-unique-by. Names are matched like
+// encoding/json matches keys. T is a struct or a pointer to one; nil
+// elements have equal keys. Errors are the same as for [UniqueItems].
+// UniqueByFields panics if T has no field for a name.
+//
+//	v.Field(&c.Items, v.UniqueByFields[LineItem]("sku", "warehouse"))
+func UniqueByFields[T any](names ...string) Rule {
+	t := reflect.TypeFor[T]()
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("apivalidation: UniqueByFields: %s is not a struct", t))
+	}
+	r := &uniqueByFieldsRule{elem: t}
+	for _, name := range names {
+		index, sf, ok := jsonFieldIndex(st, name)
+		if !ok {
+			panic(fmt.Sprintf("apivalidation: UniqueByFields: %s has no field %q", st, name))
+		}
+		if tag := strings.Split(sf.Tag.Get("json"), ",")[0]; tag != "" {
+			name = tag
+		} else {
+			name = sf.Name
+		}
+		r.fields = append(r.fields, index)
+		r.names = append(r.names, name)
+	}
+	return r
+}
+
+type uniqueByFieldsRule struct {
+	elem   reflect.Type
+	names  []string
+	fields [][]int
+}
+
+func (r *uniqueByFieldsRule) Validate(value any) error {
+	key := reflect.ArrayOf(len(r.fields), reflect.TypeFor[any]())
+	return validateUnique(value, r.elem, func(elem reflect.Value) any {
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return nil
+			}
+			elem = elem.Elem()
+		}
+		k := reflect.New(key).Elem()
+		for i, index := range r.fields {
+			// A nil embedded pointer leaves the key element nil.
+			if f, err := elem.FieldByIndexErr(index); err == nil {
+				k.Index(i).Set(f)
+			}
+		}
+		return k.Interface()
+	})
+}
+
+func (r *uniqueByFieldsRule) Describe(_ string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	if len(r.names) > 0 {
+		setExtension(ref.Value, "x-unique-by", slices.Clone(r.names))
+	}
+	return nil
+}
+
+// validateUnique reports the elements of the slice or array value whose key
+// equals the key of an earlier element. If want is not nil, the elements must
+// be assignable to it. Keys that cannot be hashed are compared with
+// [reflect.DeepEqual].
+func validateUnique(value any, want reflect.Type, key func(elem reflect.Value) any) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return ErrNotSlice.SetParams(map[string]any{"type": rv.Kind().String()})
+	}
+	if want != nil && !rv.Type().Elem().AssignableTo(want) {
+		return ErrTypeMismatch.SetParams(map[string]any{"type": rv.Type().Elem().String(), "want": want.String()})
+	}
+	errs := validation.Errors{}
+	first := map[any]int{}
+	var keys []any
+	for i := range rv.Len() {
+		k := key(rv.Index(i))
+		j, hashed := firstIndex(first, k, i)
+		if !hashed {
+			for prev, pk := range keys {
+				if reflect.DeepEqual(k, pk) {
+					j = prev
+					break
+				}
+			}
+		}
+		keys = append(keys, k)
+		if j >= 0 {
+			errs[strconv.Itoa(i)] = ErrDuplicateItem.SetParams(map[string]any{"index": i, "first": j})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// firstIndex returns the index recorded in first for k, or records i and
+// returns -1. hashed is false if k cannot be a map key: its type is not
+// comparable, or it holds a slice, map or func in an interface, which
+// panics when hashed.
+func firstIndex(first map[any]int, k any, i int) (j int, hashed bool) {
+	if k != nil && !reflect.TypeOf(k).Comparable() {
+		return -1, false
+	}
+	defer func() {
+		if recover() != nil {
+			j, hashed = -1, false
+		}
+	}()
+	if prev, seen := first[k]; seen {
+		return prev, true
+	}
+	first[k] = i
+	return -1, true
+}
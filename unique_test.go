@@ -0,0 +1,115 @@
+package apivalidation_test
+
+import (
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uniqueLine struct {
+	SKU       string `json:"sku"`
+	Warehouse string `json:"warehouse,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+type uniqueCart struct {
+	Items []uniqueLine `json:"items"`
+	Lines []uniqueLine `json:"lines"`
+	Tags  []string     `json:"tags"`
+}
+
+func (c *uniqueCart) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&c.Items, v.UniqueByFields[uniqueLine]("sku", "warehouse")),
+		v.Field(&c.Lines, v.UniqueBy(func(l uniqueLine) string { return l.SKU })),
+		v.Field(&c.Tags, v.UniqueItems()),
+	}
+}
+
+func TestUniqueBy_Validate(t *testing.T) {
+	rule := v.UniqueBy(func(l uniqueLine) string { return l.SKU })
+
+	assert.NoError(t, rule.Validate([]uniqueLine{{SKU: "a"}, {SKU: "b"}}))
+	assert.NoError(t, rule.Validate((*[]uniqueLine)(nil)))
+
+	err := rule.Validate([]uniqueLine{{SKU: "a"}, {SKU: "b"}, {SKU: "c"}, {SKU: "d"}, {SKU: "b"}})
+	require.Error(t, err)
+	errs := err.(validation.Errors)
+	require.Len(t, errs, 1)
+	verr := errs["4"].(validation.Error)
+	assert.Equal(t, v.ErrDuplicateItem.Code(), verr.Code())
+	assert.Equal(t, map[string]any{"index": 4, "first": 1}, verr.Params())
+	assert.Equal(t, "4: duplicates item 1.", err.Error())
+
+	assert.Equal(t, v.ErrTypeMismatch.Code(), rule.Validate([]string{"a"}).(validation.Error).Code())
+	assert.Equal(t, v.ErrNotSlice.Code(), rule.Validate("a").(validation.Error).Code())
+}
+
+func TestUniqueByFields_Validate(t *testing.T) {
+	rule := v.UniqueByFields[*uniqueLine]("SKU", "warehouse")
+
+	assert.NoError(t, rule.Validate([]*uniqueLine{{SKU: "a"}, {SKU: "a", Warehouse: "w"}, nil}))
+
+	err := rule.Validate([]*uniqueLine{nil, {SKU: "a", Quantity: 1}, {SKU: "a", Quantity: 2}, nil})
+	require.Error(t, err)
+	errs := err.(validation.Errors)
+	require.Len(t, errs, 2)
+	assert.Equal(t, map[string]any{"index": 2, "first": 1}, errs["2"].(validation.Error).Params())
+	assert.Equal(t, map[string]any{"index": 3, "first": 0}, errs["3"].(validation.Error).Params())
+
+	assert.Equal(t, v.ErrTypeMismatch.Code(), rule.Validate([]uniqueLine{{}}).(validation.Error).Code())
+	assert.Panics(t, func() { v.UniqueByFields[uniqueLine]("sku", "bin") })
+	assert.Panics(t, func() { v.UniqueByFields[string]("sku") })
+}
+
+func TestUniqueItems_Validate(t *testing.T) {
+	rule := v.UniqueItems()
+
+	assert.NoError(t, rule.Validate([]int{1, 2, 3}))
+
+	a, b := "x", "x"
+	err := rule.Validate([]*string{&a, nil, &b, nil})
+	require.Error(t, err)
+	errs := err.(validation.Errors)
+	assert.Equal(t, map[string]any{"index": 2, "first": 0}, errs["2"].(validation.Error).Params())
+	assert.Equal(t, map[string]any{"index": 3, "first": 1}, errs["3"].(validation.Error).Params())
+
+	// Structs holding slices in interface fields cannot be hashed, and are
+	// compared deeply.
+	type meta struct{ Meta any }
+	err = rule.Validate([]meta{{Meta: []int{1}}, {Meta: []int{2}}, {Meta: []int{1}}, {Meta: "x"}, {Meta: "x"}})
+	require.Error(t, err)
+	errs = err.(validation.Errors)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, map[string]any{"index": 2, "first": 0}, errs["2"].(validation.Error).Params())
+	assert.Equal(t, map[string]any{"index": 4, "first": 3}, errs["4"].(validation.Error).Params())
+
+	// Elements that are not comparable are compared deeply.
+	err = rule.Validate([][]int{{1}, {2}, {1}})
+	require.Error(t, err)
+	assert.Contains(t, err.(validation.Errors), "2")
+}
+
+func TestUnique_Struct(t *testing.T) {
+	c := uniqueCart{
+		Items: []uniqueLine{{SKU: "a", Warehouse: "w1"}, {SKU: "a", Warehouse: "w2"}, {SKU: "a", Warehouse: "w1", Quantity: 3}},
+		Tags:  []string{"x", "y", "x"},
+	}
+	err := v.Validate(&c)
+	require.Error(t, err)
+	assert.Equal(t, "items: (2: duplicates item 0.); tags: (2: duplicates item 0.).", err.Error())
+}
+
+func TestUnique_Schema(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(uniqueCart{})
+	require.NoError(t, err)
+
+	items := schema.Value.Properties["items"].Value
+	assert.Equal(t, []string{"sku", "warehouse"}, items.Extensions["x-unique-by"])
+	assert.False(t, items.UniqueItems)
+	assert.Empty(t, schema.Value.Properties["lines"].Value.Extensions, "a key function is not documented")
+	assert.True(t, schema.Value.Properties["tags"].Value.UniqueItems)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,18 +19,49 @@ import (
 // If value implements ValueRuler, applies its rules to the value directly.
 // Collection elements implementing Ruler are auto-validated.
 func Validate(value any) error {
-	return validateCore(context.Background(), value)
+	return ValidateCtx(context.Background(), value)
 }
 
 // ValidateCtx is like Validate but passes a context to ContextRuler.Rules().
+// If ctx carries a locale (see [WithLocale]), or [Messages] has a default
+// locale, error messages are translated using [Messages].
 func ValidateCtx(ctx context.Context, value any) error {
-	return validateCore(ctx, value)
+	return translate(ctx, validateCore(ctx, value))
+}
+
+// translate translates err with [Messages] if ctx carries a locale or a
+// default locale is set.
+func translate(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	locale := LocaleFromContext(ctx)
+	if locale == "" && Messages.DefaultLocale() == "" {
+		return err
+	}
+	return Messages.Translate(err, locale)
 }
 
 // ValidateStruct validates a struct with explicit field rules.
 // Prefer Validate for types implementing Ruler.
 func ValidateStruct(structPtr any, fields []*FieldRules) error {
-	return validation.ValidateStruct(structPtr, convertFieldRules(context.Background(), structPtr, fields...)...)
+	ctx := context.Background()
+	err := validation.ValidateStruct(structPtr, convertFieldRules(ctx, structPtr, fields...)...)
+	if !slices.ContainsFunc(fields, func(fr *FieldRules) bool { return fr.fieldPtr == nil }) {
+		return err
+	}
+	if _, ok := err.(validation.InternalError); ok {
+		return err
+	}
+	errs, _ := err.(validation.Errors)
+	if errs == nil {
+		errs = validation.Errors{}
+	}
+	applyStructRules(ctx, fields, errorKeyNamer(reflect.Indirect(reflect.ValueOf(structPtr))), errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // UnmarshalAndValidate decodes JSON from r into dst, then validates.
@@ -73,12 +105,17 @@ func validateCore(ctx context.Context, value any) error {
 		return nil
 	}
 
+	// Discriminated: validate the decoded variant.
+	if u, ok := value.(unionValue); ok {
+		return u.validateUnion(ctx)
+	}
+
 	// Ruler/ContextRuler: validate struct fields.
 	if r, ok := value.(Ruler); ok {
-		return validation.ValidateStruct(value, convertFieldRules(ctx, value, r.Rules()...)...)
+		return validateStruct(ctx, value, r.Rules())
 	}
 	if r, ok := value.(ContextRuler); ok {
-		return validation.ValidateStruct(value, convertFieldRules(ctx, value, r.Rules(ctx)...)...)
+		return validateStruct(ctx, value, r.Rules(ctx))
 	}
 	// Non-pointer struct value: check if *T implements Ruler/ContextRuler.
 	// This happens when ozzo passes a struct field value to the bridge rule.
@@ -87,10 +124,10 @@ func validateCore(ctx context.Context, value any) error {
 		ptr.Elem().Set(rv)
 		pi := ptr.Interface()
 		if r, ok := pi.(Ruler); ok {
-			return validation.ValidateStruct(pi, convertFieldRules(ctx, pi, r.Rules()...)...)
+			return validateStruct(ctx, pi, r.Rules())
 		}
 		if r, ok := pi.(ContextRuler); ok {
-			return validation.ValidateStruct(pi, convertFieldRules(ctx, pi, r.Rules(ctx)...)...)
+			return validateStruct(ctx, pi, r.Rules(ctx))
 		}
 	}
 
@@ -134,8 +171,21 @@ func validateValueRules(value any, rules []Rule) error {
 }
 
 // shouldAutoValidate checks if elements of the given type can be auto-validated.
-// Recurses into nested collections (e.g. map[string][]Ruler).
+// Recurses into nested collections (e.g. map[string][]Ruler). Results are
+// cached per type.
 func shouldAutoValidate(elemType reflect.Type) bool {
+	if b, ok := autoValidates.Load(elemType); ok {
+		return b.(bool)
+	}
+	b := computeAutoValidate(elemType)
+	autoValidates.Store(elemType, b)
+	return b
+}
+
+func computeAutoValidate(elemType reflect.Type) bool {
+	if elemType.Implements(unionValueType) {
+		return true
+	}
 	if elemType.Kind() == reflect.Struct {
 		if _, ok := reflect.New(elemType).Interface().(Ruler); ok {
 			return true
@@ -180,6 +230,10 @@ func validateElement(ctx context.Context, v reflect.Value) error {
 		}
 	}
 
+	if v.Type().Implements(unionValueType) {
+		return validateCore(ctx, v.Interface())
+	}
+
 	// Nested collections (e.g. map[string][]Ruler): delegate to validateCore.
 	switch v.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map:
@@ -191,9 +245,15 @@ func validateElement(ctx context.Context, v reflect.Value) error {
 
 func validateSlice(ctx context.Context, rv reflect.Value) error {
 	errs := validation.Errors{}
+	present := presenceFrom(ctx)
 	for i := range rv.Len() {
-		if err := validateElement(ctx, rv.Index(i)); err != nil {
-			errs[strconv.Itoa(i)] = err
+		key := strconv.Itoa(i)
+		elemCtx, ok := childContext(ctx, present, key)
+		if !ok {
+			continue
+		}
+		if err := validateElement(elemCtx, rv.Index(i)); err != nil {
+			errs[key] = err
 		}
 	}
 	if len(errs) > 0 {
@@ -204,9 +264,15 @@ func validateSlice(ctx context.Context, rv reflect.Value) error {
 
 func validateMap(ctx context.Context, rv reflect.Value) error {
 	errs := validation.Errors{}
+	present := presenceFrom(ctx)
 	for _, key := range rv.MapKeys() {
-		if err := validateElement(ctx, rv.MapIndex(key)); err != nil {
-			errs[fmt.Sprintf("%v", key.Interface())] = err
+		k := fmt.Sprintf("%v", key.Interface())
+		elemCtx, ok := childContext(ctx, present, k)
+		if !ok {
+			continue
+		}
+		if err := validateElement(elemCtx, rv.MapIndex(key)); err != nil {
+			errs[k] = err
 		}
 	}
 	if len(errs) > 0 {
@@ -234,11 +300,14 @@ func (b *rulerBridge) Validate(value any) error {
 // A rulerBridge is appended to each field so ozzo recurses into Ruler children.
 func convertFieldRules(ctx context.Context, structPtr any, fields ...*FieldRules) []*validation.FieldRules {
 	flat := expandFields(ctx, structPtr, fields)
+	flat = slices.DeleteFunc(slices.Clone(flat), func(fr *FieldRules) bool { return fr.fieldPtr == nil })
 
+	name := errorKeyNamer(reflect.Indirect(reflect.ValueOf(structPtr)))
 	vFields := make([]*validation.FieldRules, len(flat))
 	for i, fr := range flat {
 		rules := make([]validation.Rule, len(fr.rules), len(fr.rules)+1)
 		for j, r := range fr.rules {
+			bindRule(r, name)
 			rules[j] = validation.Rule(r)
 		}
 		rules = append(rules, &rulerBridge{ctx: ctx})
@@ -0,0 +1,197 @@
+package apivalidation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ValidateJSON validates the JSON document raw against schema, as returned
+// by [NewSchemaRefForValue], without decoding it into a Go value. Use it for
+// payloads whose Go type is not available, such as proxied webhooks.
+//
+// Failures are returned as [validation.Errors] keyed by JSON property name
+// and array index, like [Validate], with failures of the document itself
+// under the "" key. They carry the codes and params of the
+// equivalent rule errors (e.g. validation_required, validation_in_invalid,
+// validation_length_too_long). Keywords no rule maps to return
+// [ErrSchemaInvalid]. Malformed JSON returns the decoding error.
+//
+// Only what the schema expresses is checked: rules documented as prose,
+// such as [By] or [When] conditions, are not enforced. Pass a schema
+// generated for OpenAPI 3.0; kin-openapi does not evaluate the keywords
+// added by [OpenAPI31].
+func ValidateJSON(schema *openapi3.SchemaRef, raw []byte) error {
+	return ValidateJSONCtx(context.Background(), schema, raw)
+}
+
+// ValidateJSONCtx is like ValidateJSON but translates error messages with
+// the locale carried by ctx (see [WithLocale]).
+func ValidateJSONCtx(ctx context.Context, schema *openapi3.SchemaRef, raw []byte) error {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	err := schema.Value.VisitJSON(doc, openapi3.MultiErrors())
+	if err == nil {
+		return nil
+	}
+	var schemaErrs []*openapi3.SchemaError
+	collectSchemaErrors(err, &schemaErrs)
+	if len(schemaErrs) == 0 {
+		return validation.NewInternalError(err)
+	}
+	errs := validation.Errors{}
+	for _, se := range schemaErrs {
+		path := se.JSONPointer()
+		if se.SchemaField == "properties" {
+			// Unsupported properties are reported on their object.
+			if key, ok := unsupportedProperty(se.Reason); ok {
+				path = append(path, key)
+			}
+		}
+		e := schemaFieldError(se)
+		if len(path) == 0 {
+			// Root-level failures, such as a struct-level anyOf, are keyed
+			// by "" like struct-level rule errors.
+			if errs[""] == nil {
+				errs[""] = e
+			}
+			continue
+		}
+		mergeErrors(errs, nestError(path, e).(validation.Errors))
+	}
+	return translate(ctx, errs)
+}
+
+// collectSchemaErrors flattens the (multi) errors returned by VisitJSON.
+func collectSchemaErrors(err error, out *[]*openapi3.SchemaError) {
+	var me openapi3.MultiError
+	if errors.As(err, &me) {
+		for _, e := range me {
+			collectSchemaErrors(e, out)
+		}
+		return
+	}
+	var se *openapi3.SchemaError
+	if errors.As(err, &se) {
+		*out = append(*out, se)
+	}
+}
+
+// unsupportedProperty returns the key named by an additionalProperties
+// failure reason.
+func unsupportedProperty(reason string) (string, bool) {
+	quoted, ok := strings.CutPrefix(reason, "property ")
+	if !ok {
+		return "", false
+	}
+	quoted, ok = strings.CutSuffix(quoted, " is unsupported")
+	if !ok {
+		return "", false
+	}
+	key, err := strconv.Unquote(quoted)
+	return key, err == nil
+}
+
+// schemaFieldError returns the rule error matching the schema keyword se
+// failed.
+func schemaFieldError(se *openapi3.SchemaError) error {
+	s := se.Schema
+	switch se.SchemaField {
+	case "required":
+		return validation.ErrRequired
+	case "properties":
+		return ErrUnknownField
+	case "type", "nullable":
+		expected := "null"
+		if s.Type != nil {
+			expected = strings.Join(s.Type.Slice(), " or ")
+		}
+		return ErrJSONType.SetParams(map[string]any{"expected": expected, "actual": jsonValueKind(se.Value)})
+	case "enum":
+		return ErrInInvalid.SetParams(map[string]any{"allowed": s.Enum, "actual": se.Value})
+	case "minLength":
+		return validation.ErrLengthTooShort.SetParams(map[string]any{"min": s.MinLength, "actual": jsonLength(se.Value)})
+	case "maxLength":
+		return validation.ErrLengthTooLong.SetParams(map[string]any{"max": *s.MaxLength, "actual": jsonLength(se.Value)})
+	case "minItems":
+		return validation.ErrLengthTooShort.SetParams(map[string]any{"min": s.MinItems, "actual": jsonLength(se.Value)})
+	case "maxItems":
+		return validation.ErrLengthTooLong.SetParams(map[string]any{"max": *s.MaxItems, "actual": jsonLength(se.Value)})
+	case "minProperties":
+		return validation.ErrLengthTooShort.SetParams(map[string]any{"min": s.MinProps, "actual": jsonLength(se.Value)})
+	case "maxProperties":
+		return validation.ErrLengthTooLong.SetParams(map[string]any{"max": *s.MaxProps, "actual": jsonLength(se.Value)})
+	case "minimum":
+		return validation.ErrMinGreaterEqualThanRequired.SetParams(map[string]any{"threshold": *s.Min, "min": *s.Min, "actual": se.Value})
+	case "exclusiveMinimum":
+		return validation.ErrMinGreaterThanRequired.SetParams(map[string]any{"threshold": *s.Min, "min": *s.Min, "actual": se.Value})
+	case "maximum":
+		return validation.ErrMaxLessEqualThanRequired.SetParams(map[string]any{"threshold": *s.Max, "max": *s.Max, "actual": se.Value})
+	case "exclusiveMaximum":
+		return validation.ErrMaxLessThanRequired.SetParams(map[string]any{"threshold": *s.Max, "max": *s.Max, "actual": se.Value})
+	case "multipleOf":
+		return ErrNotMultipleOf.SetParams(map[string]any{"factor": *s.MultipleOf, "actual": se.Value})
+	case "pattern":
+		return validation.ErrMatchInvalid.SetParams(map[string]any{"pattern": s.Pattern})
+	case "uniqueItems":
+		return ErrNotUnique.SetParams(map[string]any{})
+	case "format":
+		return ErrFormatInvalid.SetParams(map[string]any{"format": s.Format})
+	case "anyOf":
+		return ErrAnyOfInvalid.SetParams(map[string]any{})
+	case "oneOf":
+		return ErrOneOfInvalid.SetParams(map[string]any{"matched": oneOfMatches(s, se.Value)})
+	case "not":
+		return ErrNotAllowed.SetParams(map[string]any{})
+	}
+	return ErrSchemaInvalid.SetParams(map[string]any{"keyword": se.SchemaField, "reason": se.Reason})
+}
+
+// oneOfMatches returns the number of oneOf subschemas of s that v matches.
+func oneOfMatches(s *openapi3.Schema, v any) int {
+	n := 0
+	for _, sub := range s.OneOf {
+		if sub.Value != nil && sub.Value.VisitJSON(v) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// jsonValueKind names the kind of a decoded JSON value, for type errors.
+func jsonValueKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "number"
+}
+
+// jsonLength returns the length of a decoded JSON string, array or object:
+// runes for strings, like [Length].
+func jsonLength(v any) int {
+	switch v := v.(type) {
+	case string:
+		return len([]rune(v))
+	case []any:
+		return len(v)
+	case map[string]any:
+		return len(v)
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package apivalidation_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	v "github.com/Gobd/apivalidation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+func (l *jsonLine) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&l.SKU, v.Required),
+		v.Field(&l.Qty, v.Min(1), v.Max(10)),
+	}
+}
+
+type jsonOrder struct {
+	Status string     `json:"status"`
+	Lines  []jsonLine `json:"lines"`
+}
+
+func (o *jsonOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&o.Status, v.Required, v.In("open", "closed")),
+		v.Field(&o.Lines),
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(jsonOrder{})
+	require.NoError(t, err)
+
+	require.NoError(t, v.ValidateJSON(schema, []byte(`{"status":"open","lines":[{"sku":"a","qty":2}]}`)))
+
+	raw := []byte(`{"status":"lost","lines":[{"sku":"a","qty":2},{"qty":11}]}`)
+	err = v.ValidateJSON(schema, raw)
+	require.Error(t, err)
+
+	var order jsonOrder
+	require.NoError(t, json.Unmarshal(raw, &order))
+	typed := v.FieldErrors(v.Validate(&order))
+	untyped := v.FieldErrors(err)
+	require.Len(t, untyped, len(typed))
+	for i := range typed {
+		assert.Equal(t, typed[i].Pointer, untyped[i].Pointer)
+		assert.Equal(t, typed[i].Code, untyped[i].Code)
+	}
+
+	status := untyped[2]
+	assert.Equal(t, "/status", status.Pointer)
+	assert.Equal(t, []any{"open", "closed"}, status.Params["allowed"])
+	assert.Equal(t, "lost", status.Params["actual"])
+}
+
+func TestValidateJSON_Types(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(jsonOrder{}, v.Strict())
+	require.NoError(t, err)
+
+	errs := v.FieldErrors(v.ValidateJSON(schema, []byte(`{"status":"open","lines":[{"sku":1}],"extra":true}`)))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/extra", errs[0].Pointer)
+	assert.Equal(t, v.ErrUnknownField.Code(), errs[0].Code)
+	assert.Equal(t, "/lines/0/sku", errs[1].Pointer)
+	assert.Equal(t, v.ErrJSONType.Code(), errs[1].Code)
+	assert.Equal(t, "number", errs[1].Params["actual"])
+
+	err = v.ValidateJSON(schema, []byte(`[]`))
+	assert.Equal(t, v.ErrJSONType.Code(), v.FieldErrors(err)[0].Code)
+
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, v.ValidateJSON(schema, []byte(`{`)), &syntaxErr)
+}
+
+func TestValidateJSON_RootErrors(t *testing.T) {
+	schema, err := v.NewSchemaRefForValue(jsonOrder{})
+	require.NoError(t, err)
+	schema.Value.MinProps = 2
+
+	errs := v.FieldErrors(v.ValidateJSON(schema, []byte(`{"status":"lost"}`)))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "", errs[0].Pointer)
+	assert.Equal(t, "validation_length_too_short", errs[0].Code)
+	assert.Equal(t, "/status", errs[1].Pointer)
+	assert.Equal(t, "validation_in_invalid", errs[1].Code)
+}
@@ -8,6 +8,7 @@ import (
 
 	v "github.com/Gobd/apivalidation"
 	"github.com/Gobd/apivalidation/transform"
+	"github.com/getkin/kin-openapi/openapi3"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -832,6 +833,57 @@ func TestEach_Invalid(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestEach_ErrorKeys(t *testing.T) {
+	err := v.Each(v.In("a", "b")).Validate([]string{"a", "x", "y"})
+	errs, ok := err.(validation.Errors)
+	require.True(t, ok)
+	assert.Len(t, errs, 2)
+	assert.Contains(t, errs, "1")
+	assert.Contains(t, errs, "2")
+
+	err = v.Each(v.Min(1)).Validate(map[string]int{"a": 1, "b": 0, "c": -1})
+	errs, ok = err.(validation.Errors)
+	require.True(t, ok)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs, "c")
+}
+
+type eachLabels struct {
+	Labels map[string]string `json:"labels"`
+}
+
+func (l *eachLabels) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&l.Labels, v.EachKey(v.Pattern(`^[a-z]+$`)), v.EachValue(v.Length(1, 5))),
+	}
+}
+
+func TestEachKeyValue(t *testing.T) {
+	require.NoError(t, v.Validate(&eachLabels{Labels: map[string]string{"env": "prod"}}))
+
+	errs := v.FieldErrors(v.Validate(&eachLabels{Labels: map[string]string{"Env": "prod", "Tier": "web"}}))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/labels/Env", errs[0].Pointer)
+	assert.Equal(t, validation.ErrMatchInvalid.Code(), errs[0].Code)
+	assert.Equal(t, "/labels/Tier", errs[1].Pointer)
+
+	errs = v.FieldErrors(v.Validate(&eachLabels{Labels: map[string]string{"tier": "backend"}}))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/labels/tier", errs[0].Pointer)
+	assert.Equal(t, "validation_length_out_of_range", errs[0].Code)
+
+	assert.Equal(t, v.ErrNotMap.Code(), v.EachKey(v.Required).Validate([]string{"a"}).(validation.Error).Code())
+
+	schema := schemaFor(t, eachLabels{})
+	labels := schema.Properties["labels"].Value
+	assert.Equal(t, `^[a-z]+$`, labels.Extensions["x-propertyNames"].(*openapi3.Schema).Pattern)
+	assert.Equal(t, uint64(1), labels.AdditionalProperties.Schema.Value.MinLength)
+
+	ref, err := v.NewSchemaRefForValue(eachLabels{}, v.OpenAPI31())
+	require.NoError(t, err)
+	assert.Contains(t, ref.Value.Properties["labels"].Value.Extensions, "propertyNames")
+}
+
 // --- StringRule ---
 
 func TestStringRuleDecimalMax_Valid(t *testing.T) {
@@ -910,6 +962,31 @@ func TestUnmarshalAndValidate_NormalizesRecursive(t *testing.T) {
 	assert.Equal(t, "SEATTLE", o.Addresses[0].City)
 }
 
+type normPaging struct {
+	Limit int
+}
+
+type normEmbedded struct {
+	normPaging
+	Name    string
+	address normAddress
+}
+
+func (e *normEmbedded) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&e.Name, v.Required),
+	}
+}
+
+func TestUnmarshalAndValidate_SkipsUnexportedFields(t *testing.T) {
+	e := normEmbedded{address: normAddress{City: " x "}}
+	require.NotPanics(t, func() {
+		assert.NoError(t, v.UnmarshalAndValidate([]byte(`{"Name":"a","Limit":5}`), &e))
+	})
+	assert.Equal(t, 5, e.Limit)
+	assert.Equal(t, " x ", e.address.City, "unexported fields are not normalized")
+}
+
 // --- StructTrimSpace ---
 
 func TestStructTrimSpace(t *testing.T) {
@@ -1066,3 +1143,77 @@ func TestMissingRules_Embedded(t *testing.T) {
 	missing := v.MissingRules(&valWithEmbed{})
 	assert.Empty(t, missing)
 }
+
+// ============ Cached struct plan vs. ozzo ValidateStruct ============
+
+type benchOrder struct {
+	valBase
+	Customer string          `json:"customer"`
+	Email    string          `json:"email"`
+	Notes    string          `json:"notes"`
+	Count    int             `json:"count"`
+	Total    float64         `json:"total"`
+	Fees     []processingFee `json:"fees"`
+}
+
+func (o *benchOrder) Rules() []*v.FieldRules {
+	return []*v.FieldRules{
+		v.Field(&o.valBase),
+		v.Field(&o.Customer, v.Required, v.Length(1, 100)),
+		v.Field(&o.Email, v.Required),
+		v.Field(&o.Notes, v.Length(0, 500)),
+		v.Field(&o.Count, v.Min(1), v.Max(1000)),
+		v.Field(&o.Total, v.Min(0.0)),
+		v.Field(&o.Fees),
+	}
+}
+
+func newBenchOrder() benchOrder {
+	return benchOrder{
+		valBase:  valBase{ID: "o-1"},
+		Customer: "Ada",
+		Email:    "ada@example.com",
+		Count:    3,
+		Total:    12.5,
+		Fees: []processingFee{
+			{PaymentType: "ach", Amount: 1},
+			{PaymentType: "cc", Amount: 2},
+			{PaymentType: "wire", Amount: 3},
+		},
+	}
+}
+
+func TestValidate_MatchesValidateStruct(t *testing.T) {
+	valid := newBenchOrder()
+	require.NoError(t, v.Validate(&valid))
+	require.NoError(t, v.ValidateStruct(&valid, valid.Rules()))
+
+	invalid := newBenchOrder()
+	invalid.ID = ""
+	invalid.Count = 0
+	invalid.Customer = strings.Repeat("x", 101)
+	invalid.Fees[1].PaymentType = "cash"
+	want := v.ValidateStruct(&invalid, invalid.Rules())
+	require.Error(t, want)
+	assert.Equal(t, want.Error(), v.Validate(&invalid).Error())
+}
+
+func BenchmarkValidate(b *testing.B) {
+	o := newBenchOrder()
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := v.Validate(&o); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateStruct(b *testing.B) {
+	o := newBenchOrder()
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := v.ValidateStruct(&o, o.Rules()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
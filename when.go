@@ -1,7 +1,9 @@
 package apivalidation
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -16,6 +18,9 @@ type WhenRule struct {
 	desc      string
 	whenRules []Rule
 	elseRules []Rule
+	ifField   fieldRef
+	ifValue   any
+	ifMatch   func() bool
 }
 
 // When returns a conditional validation rule that applies rules only when condition is true.
@@ -34,6 +39,81 @@ func (r *WhenRule) Else(rules ...Rule) *WhenRule {
 	return r
 }
 
+// IfField replaces the condition given to [When] by the field fieldPtr
+// points to being equal to value, checked each time the rule validates, and
+// applies the [WhenRule.Else] rules when it is not. [OpenAPI31] schemas
+// document the rule as if/then/else on the parent instead of prose. value
+// is converted to the field's type, so untyped constants work for named
+// types. IfField panics if fieldPtr is not a pointer or value cannot be
+// converted.
+//
+//	v.Field(&o.CardNumber, v.When(true, "method is card", v.Required).IfField(&o.Method, "card"))
+func (r *WhenRule) IfField(fieldPtr any, value any) *WhenRule {
+	field := reflect.ValueOf(fieldPtr)
+	if field.Kind() != reflect.Ptr || field.IsNil() {
+		panic(fmt.Sprintf("apivalidation: IfField: %T is not a pointer to a field", fieldPtr))
+	}
+	ft := field.Type().Elem()
+	want, ok := convertExact(reflect.ValueOf(value), ft)
+	if !ok || !ft.Comparable() {
+		panic(fmt.Sprintf("apivalidation: IfField: cannot compare %s with %T", ft, value))
+	}
+	r.ifField = fieldRef{ptr: fieldPtr}
+	r.ifValue = value
+	r.ifMatch = func() bool { return field.Elem().Equal(want) }
+	return r
+}
+
+// convertExact converts v to t if that keeps its value, so 3 converts to
+// int64 or float64 but neither 3.5 to int nor 65 to string.
+func convertExact(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !v.IsValid() || !v.Type().ConvertibleTo(t) {
+		return reflect.Value{}, false
+	}
+	if t.Kind() == reflect.String && v.Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+	c := v.Convert(t)
+	if t.Kind() != reflect.Interface && (!t.ConvertibleTo(v.Type()) || !c.Convert(v.Type()).Equal(v)) {
+		return reflect.Value{}, false
+	}
+	return c, true
+}
+
+// Validate validates value with the rules of the branch the condition
+// selects.
+func (r *WhenRule) Validate(value any) error {
+	return r.rule().Validate(value)
+}
+
+// ValidateWithContext is like Validate, passing ctx to the rules.
+func (r *WhenRule) ValidateWithContext(ctx context.Context, value any) error {
+	return r.rule().ValidateWithContext(ctx, value)
+}
+
+// rule returns the ozzo rule to validate with, evaluating an
+// [WhenRule.IfField] condition now.
+func (r *WhenRule) rule() validation.WhenRule {
+	if r.ifMatch == nil {
+		return r.WhenRule
+	}
+	return validation.When(r.ifMatch(), convertRules(r.whenRules...)...).Else(convertRules(r.elseRules...)...)
+}
+
+// bindFields forwards field names to conditional rules that refer to other
+// fields (e.g. [RequiredIf]).
+func (r *WhenRule) bindFields(name func(fieldPtr any) string) {
+	if r.ifField.ptr != nil {
+		r.ifField.bind(name)
+	}
+	for _, rule := range r.whenRules {
+		bindRule(rule, name)
+	}
+	for _, rule := range r.elseRules {
+		bindRule(rule, name)
+	}
+}
+
 // describeRules calls Describe on each rule using a temporary schema/ref,
 // then extracts a human-readable summary of the schema mutations.
 func describeRules(name string, rules []Rule) (string, error) {
@@ -56,28 +136,61 @@ func describeRules(name string, rules []Rule) (string, error) {
 		parts = append(parts, ref.Value.Description)
 	}
 	if len(schema.Required) > 0 {
-		parts = append(parts, "required")
+		parts = append(parts, Messages.Describe("describe_required", "required", nil))
+	}
+	switch {
+	case ref.Value.Min != nil && ref.Value.ExclusiveMin:
+		parts = append(parts, Messages.Describe("describe_exclusive_min", "greater than {{.min}}", map[string]any{"min": fmt.Sprintf("%g", *ref.Value.Min)}))
+	case ref.Value.Min != nil:
+		parts = append(parts, Messages.Describe("describe_min", "min {{.min}}", map[string]any{"min": fmt.Sprintf("%g", *ref.Value.Min)}))
+	}
+	switch {
+	case ref.Value.Max != nil && ref.Value.ExclusiveMax:
+		parts = append(parts, Messages.Describe("describe_exclusive_max", "less than {{.max}}", map[string]any{"max": fmt.Sprintf("%g", *ref.Value.Max)}))
+	case ref.Value.Max != nil:
+		parts = append(parts, Messages.Describe("describe_max", "max {{.max}}", map[string]any{"max": fmt.Sprintf("%g", *ref.Value.Max)}))
 	}
-	if ref.Value.Min != nil {
-		parts = append(parts, fmt.Sprintf("min %g", *ref.Value.Min))
+	if ref.Value.MultipleOf != nil {
+		parts = append(parts, Messages.Describe("describe_multiple_of", "multiple of {{.factor}}", map[string]any{"factor": fmt.Sprintf("%g", *ref.Value.MultipleOf)}))
 	}
-	if ref.Value.Max != nil {
-		parts = append(parts, fmt.Sprintf("max %g", *ref.Value.Max))
+	for _, size := range []struct {
+		code, fallback string
+		n              *uint64
+	}{
+		{"describe_min_length", "min length {{.min}}", nonZero(ref.Value.MinLength)},
+		{"describe_max_length", "max length {{.max}}", ref.Value.MaxLength},
+		{"describe_min_items", "min items {{.min}}", nonZero(ref.Value.MinItems)},
+		{"describe_max_items", "max items {{.max}}", ref.Value.MaxItems},
+		{"describe_min_properties", "min properties {{.min}}", nonZero(ref.Value.MinProps)},
+		{"describe_max_properties", "max properties {{.max}}", ref.Value.MaxProps},
+	} {
+		if size.n != nil {
+			parts = append(parts, Messages.Describe(size.code, size.fallback, map[string]any{"min": *size.n, "max": *size.n}))
+		}
 	}
 	if len(ref.Value.Enum) > 0 {
 		vals := make([]string, len(ref.Value.Enum))
 		for i, v := range ref.Value.Enum {
 			vals[i] = fmt.Sprint(v)
 		}
-		parts = append(parts, "one of ["+strings.Join(vals, ", ")+"]")
+		parts = append(parts, Messages.Describe("describe_one_of", "one of [{{.list}}]",
+			map[string]any{"allowed": ref.Value.Enum, "list": strings.Join(vals, ", ")}))
 	}
 	if ref.Value.UniqueItems {
-		parts = append(parts, "unique")
+		parts = append(parts, Messages.Describe("describe_unique", "unique", nil))
 	}
 
 	return strings.Join(parts, ", "), nil
 }
 
+// nonZero returns a pointer to n, or nil if n is 0.
+func nonZero(n uint64) *uint64 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
 // Describe implements [Rule] by appending a human-readable summary of the
 // conditional rules to the schema description.
 func (r *WhenRule) Describe(name string, _ *openapi3.Schema, ref *openapi3.SchemaRef) error {
@@ -91,7 +204,8 @@ func (r *WhenRule) Describe(name string, _ *openapi3.Schema, ref *openapi3.Schem
 				ref.Value.Description += " "
 			}
 			if r.desc != "" {
-				ref.Value.Description += fmt.Sprintf("when %s: %s", r.desc, desc)
+				ref.Value.Description += Messages.Describe("describe_when", "when {{.condition}}: {{.rules}}",
+					map[string]any{"condition": r.desc, "rules": desc})
 			} else {
 				ref.Value.Description += desc
 			}
@@ -107,8 +221,32 @@ func (r *WhenRule) Describe(name string, _ *openapi3.Schema, ref *openapi3.Schem
 			if ref.Value.Description != "" && !strings.HasSuffix(ref.Value.Description, " ") {
 				ref.Value.Description += " "
 			}
-			ref.Value.Description += "else: " + desc
+			ref.Value.Description += Messages.Describe("describe_else", "else: {{.rules}}", map[string]any{"rules": desc})
+		}
+	}
+	return nil
+}
+
+// describe31 documents a rule with an [WhenRule.IfField] condition as an
+// allOf entry of the parent holding if/then/else. Other rules, and rules
+// without a parent, are described as prose, like in OpenAPI 3.0.
+func (r *WhenRule) describe31(name string, schema *openapi3.Schema, ref *openapi3.SchemaRef) error {
+	if r.ifField.name == "" || schema == nil {
+		return r.Describe(name, schema, ref)
+	}
+	entry := &openapi3.Schema{Extensions: map[string]any{"if": ifFieldEquals(r.ifField.name, r.ifValue)}}
+	then, err := conditionalSchema(name, r.whenRules)
+	if err != nil {
+		return err
+	}
+	entry.Extensions["then"] = then
+	if len(r.elseRules) > 0 {
+		otherwise, err := conditionalSchema(name, r.elseRules)
+		if err != nil {
+			return err
 		}
+		entry.Extensions["else"] = otherwise
 	}
+	schema.AllOf = append(schema.AllOf, &openapi3.SchemaRef{Value: entry})
 	return nil
 }